@@ -76,12 +76,13 @@ type claudeResponse struct {
 	StopReason string `json:"stop_reason"`
 }
 
-func (c *Client) SendMessage(ctx context.Context, messages []entity.AIMessage) (resp *entity.AIResponse, err error) {
+func (c *Client) SendMessage(ctx context.Context, messages []entity.AIMessage, resultSchema map[string]interface{}) (resp *entity.AIResponse, err error) {
 	const op = "SendMessage"
 	logger := c.logger.With(zap.String(logg.Operation, op))
 
 	ctx, step := tracing.StartSpan(ctx, c.tracer, logger, op,
-		attribute.Int("messages_count", len(messages)))
+		attribute.Int("messages_count", len(messages)),
+		attribute.Bool("structured_result", resultSchema != nil))
 	defer func() {
 		step.End(err)
 	}()
@@ -100,7 +101,7 @@ func (c *Client) SendMessage(ctx context.Context, messages []entity.AIMessage) (
 		Model:     c.config.AIConfig.Model,
 		MaxTokens: 4096,
 		Messages:  claudeMessages,
-		Tools:     c.createTools(),
+		Tools:     c.createTools(resultSchema),
 	}
 
 	step.AddEvent("marshaling request")
@@ -169,7 +170,7 @@ func (c *Client) SendMessage(ctx context.Context, messages []entity.AIMessage) (
 
 	step.AddEvent("parsing response")
 
-	aiResp, err := c.parseResponse(&claudeResp)
+	aiResp, err := c.parseResponse(&claudeResp, resultSchema)
 	if err != nil {
 		return nil, err
 	}
@@ -179,7 +180,7 @@ func (c *Client) SendMessage(ctx context.Context, messages []entity.AIMessage) (
 	return aiResp, nil
 }
 
-func (c *Client) createTools() []claudeTool {
+func (c *Client) createTools(resultSchema map[string]interface{}) []claudeTool {
 	return []claudeTool{
 		{
 			Name:        "navigate",
@@ -194,17 +195,43 @@ func (c *Client) createTools() []claudeTool {
 				"required": []string{"url"},
 			},
 		},
+		{
+			Name:        "go_back",
+			Description: "Go back in browser history (like the Back button)",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			Name:        "go_forward",
+			Description: "Go forward in browser history",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			Name:        "reload",
+			Description: "Reload the current page",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
 		{
 			Name:        "click",
-			Description: "Click element. Prefer [data-qa] selectors!",
+			Description: "Click element by its [id] from the element list, or by selector",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
 					"selector": map[string]interface{}{
 						"type": "string",
 					},
 				},
-				"required": []string{"selector"},
 			},
 		},
 		{
@@ -225,23 +252,145 @@ func (c *Client) createTools() []claudeTool {
 		},
 		{
 			Name:        "fill",
-			Description: "Fill input",
+			Description: "Fill input by its [id] from the element list, or by selector. Set type to clear the field and type the value literally, key by key",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
 					"selector": map[string]interface{}{
 						"type": "string",
 					},
 					"value": map[string]interface{}{
 						"type": "string",
 					},
+					"type": map[string]interface{}{
+						"type": "boolean",
+					},
+				},
+				"required": []string{"value"},
+			},
+		},
+		{
+			Name:        "type_text",
+			Description: "Type text key by key into an element (or the focused one) for autocomplete, masked and key-driven inputs. Embed keys as {Enter} or {Control+A}. Reports the field value and autocomplete suggestions",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
+					"text": map[string]interface{}{
+						"type": "string",
+					},
+					"delay_ms": map[string]interface{}{
+						"type": "integer",
+					},
+					"clear": map[string]interface{}{
+						"type": "boolean",
+					},
+				},
+				"required": []string{"text"},
+			},
+		},
+		{
+			Name:        "select_option",
+			Description: "Select an option in a native <select> or a custom dropdown/listbox by the option's label or value",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
+					"value": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"required": []string{"value"},
+			},
+		},
+		{
+			Name:        "check",
+			Description: "Check a checkbox, radio button or switch and verify it is checked",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
+				},
+			},
+		},
+		{
+			Name:        "uncheck",
+			Description: "Uncheck a checkbox or switch and verify it is unchecked",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
+				},
+			},
+		},
+		{
+			Name:        "hover",
+			Description: "Move the mouse over an element, e.g. to open a menu that appears on mouseover",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
+				},
+			},
+		},
+		{
+			Name:        "drag",
+			Description: "Drag an element onto another element or to page coordinates (sliders, sortable lists)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
+					"target_element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"target_selector": map[string]interface{}{
+						"type": "string",
+					},
+					"x": map[string]interface{}{
+						"type": "number",
+					},
+					"y": map[string]interface{}{
+						"type": "number",
+					},
 				},
-				"required": []string{"selector", "value"},
 			},
 		},
 		{
 			Name:        "press",
-			Description: "Press keyboard key (e.g. Enter, Escape, Tab)",
+			Description: "Press keyboard key, chord or space-separated sequence (e.g. Enter, Control+A, \"Control+A Backspace\")",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -254,7 +403,7 @@ func (c *Client) createTools() []claudeTool {
 		},
 		{
 			Name:        "scroll",
-			Description: "Scroll: down/up/bottom/top",
+			Description: "Scroll: down/up/bottom/top. Scrolls the main scrollable pane, or the panel/list/modal given by element_id or selector",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -266,11 +415,197 @@ func (c *Client) createTools() []claudeTool {
 						"type":    "number",
 						"default": 500,
 					},
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
 				},
 				"required": []string{"direction"},
 			},
 		},
 		{
+			Name:        "scroll_until",
+			Description: "Keep scrolling an infinite feed until text appears, until_selector (CSS) matches count elements, or the list holds count items. Stops when the content stops growing",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"text": map[string]interface{}{
+						"type": "string",
+					},
+					"until_selector": map[string]interface{}{
+						"type": "string",
+					},
+					"count": map[string]interface{}{
+						"type": "integer",
+					},
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
+				},
+			},
+		},
+		{
+			Name:        "list_tabs",
+			Description: "List open browser tabs with their ids",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			Name:        "switch_tab",
+			Description: "Switch to tab by id (see list_tabs)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"tab_id": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+				"required": []string{"tab_id"},
+			},
+		},
+		{
+			Name:        "close_tab",
+			Description: "Close tab by id",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"tab_id": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+				"required": []string{"tab_id"},
+			},
+		},
+		{
+			Name:        "new_tab",
+			Description: "Open a new tab, optionally at URL, and switch to it",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"url": map[string]interface{}{
+						"type": "string",
+					},
+				},
+			},
+		},
+		{
+			Name:        "upload_file",
+			Description: "Attach an approved file (by alias) to a file input or upload button. Use neither element_id nor selector when a file chooser is already open",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
+					"file_alias": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"required": []string{"file_alias"},
+			},
+		},
+		{
+			Name:        "handle_dialog",
+			Description: "Answer the open JavaScript alert/confirm/prompt dialog",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"accept": map[string]interface{}{
+						"type": "boolean",
+					},
+					"prompt_text": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"required": []string{"accept"},
+			},
+		},
+		{
+			Name:        "find_text",
+			Description: "Find text anywhere on the page, including offscreen content. Returns matching elements with ids, coordinates and context. exact matches the whole element text case-sensitively; scroll brings the best match into view",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{
+						"type": "string",
+					},
+					"exact": map[string]interface{}{
+						"type": "boolean",
+					},
+					"scroll": map[string]interface{}{
+						"type": "boolean",
+					},
+				},
+				"required": []string{"query"},
+			},
+		},
+		{
+			Name:        "extract_data",
+			Description: "Extract repeated records (table rows, search results, product or job cards) as JSON rows with inferred field names. Pass the container element_id/selector, or nothing to auto-detect",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
+					"limit": map[string]interface{}{
+						"type":    "integer",
+						"default": 50,
+					},
+				},
+			},
+		},
+		{
+			Name:        "read_page",
+			Description: "Read the page text as Markdown with links. Without element_id/selector the main content is read. Long content is split into pages starting at 1",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"element_id": map[string]interface{}{
+						"type": "integer",
+					},
+					"selector": map[string]interface{}{
+						"type": "string",
+					},
+					"page": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+			},
+		},
+		{
+			Name:        "ask_user",
+			Description: "Hand control to the human: use for CAPTCHA, 2FA/SMS codes, unfamiliar widgets or information only the user knows. The user may operate the browser and/or type an answer",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"question": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"required": []string{"question"},
+			},
+		},
+		c.completeTaskTool(resultSchema),
+	}
+}
+
+func (c *Client) completeTaskTool(resultSchema map[string]interface{}) claudeTool {
+	if resultSchema == nil {
+		return claudeTool{
 			Name:        "complete_task",
 			Description: "Complete with result",
 			InputSchema: map[string]interface{}{
@@ -282,11 +617,23 @@ func (c *Client) createTools() []claudeTool {
 				},
 				"required": []string{"result"},
 			},
+		}
+	}
+
+	return claudeTool{
+		Name:        "complete_task",
+		Description: "Complete with structured result. The result MUST match the given JSON schema exactly",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"result": resultSchema,
+			},
+			"required": []string{"result"},
 		},
 	}
 }
 
-func (c *Client) parseResponse(resp *claudeResponse) (*entity.AIResponse, error) {
+func (c *Client) parseResponse(resp *claudeResponse, resultSchema map[string]interface{}) (*entity.AIResponse, error) {
 	aiResp := &entity.AIResponse{
 		Complete: resp.StopReason == "end_turn",
 	}
@@ -305,7 +652,9 @@ func (c *Client) parseResponse(resp *claudeResponse) (*entity.AIResponse, error)
 			if content.Name == "complete_task" {
 				aiResp.Complete = true
 
-				if result, ok := content.Input["result"].(string); ok {
+				if resultSchema != nil {
+					c.parseStructuredResult(aiResp, content.Input["result"], resultSchema)
+				} else if result, ok := content.Input["result"].(string); ok {
 					aiResp.Result = result
 				}
 			}
@@ -315,6 +664,40 @@ func (c *Client) parseResponse(resp *claudeResponse) (*entity.AIResponse, error)
 	return aiResp, nil
 }
 
+func (c *Client) parseStructuredResult(aiResp *entity.AIResponse, raw interface{}, resultSchema map[string]interface{}) {
+	// Models sometimes send the structured value encoded as a JSON string.
+	// When the schema allows a string, the string is the answer and "123" or
+	// "null" must stay as they are.
+	if str, ok := raw.(string); ok && !allowsString(resultSchema) {
+		var decoded interface{}
+
+		if err := json.Unmarshal([]byte(str), &decoded); err == nil {
+			raw = decoded
+		}
+	}
+
+	aiResp.StructuredResult = raw
+
+	if encoded, err := json.Marshal(raw); err == nil {
+		aiResp.Result = string(encoded)
+	}
+}
+
+func allowsString(schema map[string]interface{}) bool {
+	switch t := schema["type"].(type) {
+	case string:
+		return t == "string"
+	case []interface{}:
+		for _, item := range t {
+			if item == "string" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (c *Client) parseToolUse(toolName string, input map[string]interface{}) (*entity.BrowserAction, error) {
 	action := &entity.BrowserAction{}
 
@@ -331,6 +714,10 @@ func (c *Client) parseToolUse(toolName string, input map[string]interface{}) (*e
 		if selector, ok := input["selector"].(string); ok {
 			action.Selector = selector
 		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
 	case "click_at_coordinates":
 		action.Type = entity.ActionTypeClickCoordinates
 
@@ -348,9 +735,109 @@ func (c *Client) parseToolUse(toolName string, input map[string]interface{}) (*e
 			action.Selector = selector
 		}
 
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+
 		if value, ok := input["value"].(string); ok {
 			action.Value = value
 		}
+
+		if typed, ok := input["type"].(bool); ok {
+			action.SimulateTyping = typed
+		}
+	case "type_text":
+		action.Type = entity.ActionTypeTypeText
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+
+		if text, ok := input["text"].(string); ok {
+			action.Value = text
+		}
+
+		if delay, ok := input["delay_ms"].(float64); ok {
+			action.Delay = int(delay)
+		}
+
+		if clear, ok := input["clear"].(bool); ok {
+			action.Clear = clear
+		}
+	case "select_option":
+		action.Type = entity.ActionTypeSelect
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+
+		if value, ok := input["value"].(string); ok {
+			action.Value = value
+		}
+	case "check":
+		action.Type = entity.ActionTypeCheck
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+	case "uncheck":
+		action.Type = entity.ActionTypeUncheck
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+	case "hover":
+		action.Type = entity.ActionTypeHover
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+	case "drag":
+		action.Type = entity.ActionTypeDrag
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+
+		if target, ok := input["target_selector"].(string); ok {
+			action.Target = target
+		}
+
+		if id, ok := input["target_element_id"].(float64); ok {
+			action.TargetID = int(id)
+		}
+
+		if x, ok := input["x"].(float64); ok {
+			action.X = x
+		}
+
+		if y, ok := input["y"].(float64); ok {
+			action.Y = y
+		}
 	case "press":
 		action.Type = entity.ActionTypePress
 
@@ -369,6 +856,134 @@ func (c *Client) parseToolUse(toolName string, input map[string]interface{}) (*e
 		} else {
 			action.WaitFor = 500
 		}
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+	case "scroll_until":
+		action.Type = entity.ActionTypeScrollUntil
+
+		if text, ok := input["text"].(string); ok {
+			action.Value = text
+		}
+
+		if until, ok := input["until_selector"].(string); ok {
+			action.Target = until
+		}
+
+		if count, ok := input["count"].(float64); ok {
+			action.Limit = int(count)
+		}
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+	case "list_tabs":
+		action.Type = entity.ActionTypeListTabs
+	case "switch_tab":
+		action.Type = entity.ActionTypeSwitchTab
+
+		if tabID, ok := input["tab_id"].(float64); ok {
+			action.TabID = int(tabID)
+		}
+	case "close_tab":
+		action.Type = entity.ActionTypeCloseTab
+
+		if tabID, ok := input["tab_id"].(float64); ok {
+			action.TabID = int(tabID)
+		}
+	case "new_tab":
+		action.Type = entity.ActionTypeNewTab
+
+		if url, ok := input["url"].(string); ok {
+			action.URL = url
+		}
+	case "upload_file":
+		action.Type = entity.ActionTypeUploadFile
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+
+		if alias, ok := input["file_alias"].(string); ok {
+			action.Value = alias
+		}
+	case "handle_dialog":
+		action.Type = entity.ActionTypeHandleDialog
+
+		if accept, ok := input["accept"].(bool); ok {
+			action.Accept = accept
+		}
+
+		if promptText, ok := input["prompt_text"].(string); ok {
+			action.Value = promptText
+		}
+	case "go_back":
+		action.Type = entity.ActionTypeGoBack
+	case "go_forward":
+		action.Type = entity.ActionTypeGoForward
+	case "reload":
+		action.Type = entity.ActionTypeReload
+	case "find_text":
+		action.Type = entity.ActionTypeFindText
+
+		if query, ok := input["query"].(string); ok {
+			action.Value = query
+		}
+
+		if exact, ok := input["exact"].(bool); ok {
+			action.Exact = exact
+		}
+
+		if scroll, ok := input["scroll"].(bool); ok {
+			action.ScrollIntoView = scroll
+		}
+	case "extract_data":
+		action.Type = entity.ActionTypeExtractData
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+
+		if limit, ok := input["limit"].(float64); ok {
+			action.Limit = int(limit)
+		}
+	case "read_page":
+		action.Type = entity.ActionTypeReadPage
+
+		if selector, ok := input["selector"].(string); ok {
+			action.Selector = selector
+		}
+
+		if id, ok := input["element_id"].(float64); ok {
+			action.ElementID = int(id)
+		}
+
+		if page, ok := input["page"].(float64); ok {
+			action.Page = int(page)
+		}
+	case "ask_user":
+		action.Type = entity.ActionTypeAskUser
+
+		if question, ok := input["question"].(string); ok {
+			action.Value = question
+		}
 	case "wait":
 		action.Type = entity.ActionTypeWait
 
@@ -387,6 +1002,6 @@ func (c *Client) parseToolUse(toolName string, input map[string]interface{}) (*e
 
 func (c *Client) CreateTools() []interface{} {
 	return []interface{}{
-		c.createTools(),
+		c.createTools(nil),
 	}
 }
@@ -5,7 +5,10 @@ import (
 	"ai-agent-task/internal/browser"
 	"ai-agent-task/internal/config"
 	"ai-agent-task/internal/console"
+	"ai-agent-task/internal/files"
+	"ai-agent-task/internal/policy"
 	"ai-agent-task/internal/ports"
+	"ai-agent-task/internal/secrets"
 	"ai-agent-task/internal/usecase"
 	"time"
 
@@ -16,14 +19,20 @@ func NewApp() *fx.App {
 	return fx.New(
 		fx.Provide(
 			config.GetConfig,
+			newRedactor,
 			newLogger,
 			newTraceProvider,
 
 			fx.Annotate(browser.NewManager, fx.As(new(ports.BrowserManager))),
 			fx.Annotate(ai.NewClient, fx.As(new(ports.AIClient))),
+			policy.NewEngine,
+			policy.NewURLFilter,
+			secrets.NewVault,
+			files.NewLibrary,
 
 			usecase.NewUsecase,
 
+			fx.Annotate(console.NewOperator, fx.As(fx.Self()), fx.As(new(ports.Operator))),
 			console.NewInterface,
 		),
 
@@ -2,11 +2,13 @@ package bootstrap
 
 import (
 	"ai-agent-task/internal/config"
+	"ai-agent-task/pkg/redact"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-func newLogger(config *config.Config) (*zap.Logger, error) {
+func newLogger(config *config.Config, redactor *redact.Redactor) (*zap.Logger, error) {
 	var zapConfig zap.Config
 
 	if config.AppConfig.Debug {
@@ -28,7 +30,9 @@ func newLogger(config *config.Config) (*zap.Logger, error) {
 		zapConfig.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
 	}
 
-	logger, err := zapConfig.Build()
+	logger, err := zapConfig.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return redact.WrapCore(core, redactor)
+	}))
 	if err != nil {
 		return nil, err
 	}
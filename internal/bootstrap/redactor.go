@@ -0,0 +1,19 @@
+package bootstrap
+
+import (
+	"ai-agent-task/internal/config"
+	"ai-agent-task/internal/secrets"
+	"ai-agent-task/pkg/redact"
+	"ai-agent-task/pkg/tracing"
+)
+
+func newRedactor(config *config.Config, vault *secrets.Vault) (*redact.Redactor, error) {
+	redactor, err := redact.New(config.RedactionConfig.Detectors, vault.Mask)
+	if err != nil {
+		return nil, err
+	}
+
+	tracing.SetRedactor(redactor.String)
+
+	return redactor, nil
+}
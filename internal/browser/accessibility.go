@@ -0,0 +1,333 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+const (
+	PageStateDOM           = "dom"
+	PageStateAccessibility = "accessibility"
+
+	maxAXNodes = 300
+	maxAXBoxes = 80
+	maxAXName  = 120
+)
+
+var interactiveRoles = map[string]bool{
+	"button":           true,
+	"link":             true,
+	"textbox":          true,
+	"searchbox":        true,
+	"combobox":         true,
+	"checkbox":         true,
+	"radio":            true,
+	"switch":           true,
+	"tab":              true,
+	"menuitem":         true,
+	"menuitemcheckbox": true,
+	"menuitemradio":    true,
+	"option":           true,
+	"slider":           true,
+	"spinbutton":       true,
+	"listbox":          true,
+	"treeitem":         true,
+}
+
+// Structural roles that only add noise when they have no name.
+var transparentRoles = map[string]bool{
+	"none":                 true,
+	"generic":              true,
+	"presentation":         true,
+	"group":                true,
+	"LineBreak":            true,
+	"InlineTextBox":        true,
+	"RootWebArea":          true,
+	"Section":              true,
+	"paragraph":            true,
+	"list":                 true,
+	"listitem":             true,
+	"LayoutTable":          true,
+	"LayoutTableRow":       true,
+	"LayoutTableCell":      true,
+	"ListMarker":           true,
+	"sectionheader":        true,
+	"sectionfooter":        true,
+	"DescriptionList":      true,
+	"figure":               true,
+	"Canvas":               true,
+	"Pre":                  true,
+	"Iframe":               true,
+	"IframePresentational": true,
+}
+
+// Roles whose value is what the user typed.
+var valueRoles = map[string]bool{
+	"textbox":    true,
+	"searchbox":  true,
+	"combobox":   true,
+	"spinbutton": true,
+}
+
+// sensitiveFieldPattern mirrors isSensitive in sensitiveInputJS for the
+// name and id of a field.
+var sensitiveFieldPattern = regexp.MustCompile(`(^|[^a-z])(password|passwd|pin|cvv|cvc|csc|card(number|num|no)?|cc|otp)([^a-z]|$)`)
+
+var axStateProperties = []string{
+	"checked", "pressed", "selected", "expanded", "disabled",
+	"required", "readonly", "invalid", "focused", "level",
+}
+
+type axNode struct {
+	role     string
+	name     string
+	value    string
+	states   []string
+	children []string
+	backend  int
+	ignored  bool
+}
+
+// accessibilityNodes reads the Chromium accessibility tree of the active page
+// and flattens it into role/name nodes with role+name selectors.
+func (m *Manager) accessibilityNodes(ctx context.Context) ([]entity.AXNode, error) {
+	session, err := m.browserContext.NewCDPSession(m.page)
+	if err != nil {
+		return nil, fmt.Errorf("open cdp session: %w", err)
+	}
+	defer session.Detach()
+
+	raw, err := session.Send("Accessibility.getFullAXTree", map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("get accessibility tree: %w", err)
+	}
+
+	result, _ := raw.(map[string]interface{})
+	rawNodes, _ := result["nodes"].([]interface{})
+
+	nodes := make(map[string]*axNode, len(rawNodes))
+	rootID := ""
+
+	for _, item := range rawNodes {
+		nodeMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		id := getString(nodeMap, "nodeId")
+		node := parseAXNode(nodeMap)
+		nodes[id] = node
+
+		if rootID == "" && getString(nodeMap, "parentId") == "" {
+			rootID = id
+		}
+	}
+
+	var out []entity.AXNode
+	seen := make(map[string]int)
+	seenRole := make(map[string]int)
+	boxes := 0
+
+	var walk func(id string, depth int)
+	walk = func(id string, depth int) {
+		node, ok := nodes[id]
+		if !ok || len(out) >= maxAXNodes {
+			return
+		}
+
+		emit := !node.ignored && !(transparentRoles[node.role] && node.name == "")
+		if node.role == "StaticText" && (len(node.name) < 3 || parentHasName(out, depth, node.name)) {
+			emit = false
+		}
+
+		childDepth := depth
+
+		if emit {
+			entry := entity.AXNode{
+				Role:   node.role,
+				Name:   truncateName(node.name),
+				Value:  truncateName(node.value),
+				States: node.states,
+				Depth:  depth,
+			}
+
+			if entry.Value != "" && valueRoles[node.role] && (node.backend == 0 || sensitiveNode(session, node.backend)) {
+				entry.Value = ""
+			}
+
+			if interactiveRoles[node.role] {
+				// Without a name the locator matches every node of the
+				// role, so the index counts all of them.
+				index := seenRole[node.role]
+				if node.name != "" {
+					key := node.role + "\x00" + node.name
+					index = seen[key]
+					seen[key]++
+				}
+
+				entry.Selector = formatRoleSelector(node.role, node.name, index)
+				seenRole[node.role]++
+
+				if boxes < maxAXBoxes && node.backend != 0 {
+					if box, ok := boxModel(session, node.backend); ok {
+						entry.BoundingBox = box
+						boxes++
+					}
+				}
+			}
+
+			out = append(out, entry)
+			childDepth = depth + 1
+		}
+
+		for _, child := range node.children {
+			walk(child, childDepth)
+		}
+	}
+
+	walk(rootID, 0)
+
+	return out, nil
+}
+
+func parseAXNode(nodeMap map[string]interface{}) *axNode {
+	node := &axNode{
+		ignored: getBool(nodeMap, "ignored"),
+		backend: int(getFloat(nodeMap, "backendDOMNodeId")),
+	}
+
+	if role, ok := nodeMap["role"].(map[string]interface{}); ok {
+		node.role = fmt.Sprint(role["value"])
+	}
+
+	if name, ok := nodeMap["name"].(map[string]interface{}); ok {
+		node.name = strings.TrimSpace(fmt.Sprint(name["value"]))
+	}
+
+	if value, ok := nodeMap["value"].(map[string]interface{}); ok && value["value"] != nil {
+		node.value = strings.TrimSpace(fmt.Sprint(value["value"]))
+	}
+
+	if childIDs, ok := nodeMap["childIds"].([]interface{}); ok {
+		for _, child := range childIDs {
+			node.children = append(node.children, fmt.Sprint(child))
+		}
+	}
+
+	props, _ := nodeMap["properties"].([]interface{})
+	values := make(map[string]string, len(props))
+
+	for _, item := range props {
+		prop, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if value, ok := prop["value"].(map[string]interface{}); ok {
+			values[getString(prop, "name")] = fmt.Sprint(value["value"])
+		}
+	}
+
+	for _, name := range axStateProperties {
+		value, ok := values[name]
+		if !ok || value == "false" || value == "" {
+			continue
+		}
+
+		switch value {
+		case "true":
+			node.states = append(node.states, name)
+		default:
+			node.states = append(node.states, name+"="+value)
+		}
+	}
+
+	return node
+}
+
+// sensitiveNode reports whether a field holds a password, payment or
+// one-time code, judged like isSensitive in DOM mode. Fields that cannot be
+// described count as sensitive.
+func sensitiveNode(session playwright.CDPSession, backendNodeID int) bool {
+	raw, err := session.Send("DOM.describeNode", map[string]interface{}{"backendNodeId": backendNodeID})
+	if err != nil {
+		return true
+	}
+
+	result, _ := raw.(map[string]interface{})
+	node, _ := result["node"].(map[string]interface{})
+	list, _ := node["attributes"].([]interface{})
+
+	attrs := make(map[string]string, len(list)/2)
+	for i := 0; i+1 < len(list); i += 2 {
+		attrs[strings.ToLower(fmt.Sprint(list[i]))] = strings.ToLower(fmt.Sprint(list[i+1]))
+	}
+
+	if _, ok := attrs["data-sensitive"]; ok || attrs["type"] == "password" {
+		return true
+	}
+
+	auto := attrs["autocomplete"]
+	if strings.HasPrefix(auto, "cc-") || auto == "one-time-code" || strings.HasSuffix(auto, "password") {
+		return true
+	}
+
+	return sensitiveFieldPattern.MatchString(attrs["name"] + " " + attrs["id"])
+}
+
+func boxModel(session playwright.CDPSession, backendNodeID int) (entity.BoundingBox, bool) {
+	raw, err := session.Send("DOM.getBoxModel", map[string]interface{}{"backendNodeId": backendNodeID})
+	if err != nil {
+		return entity.BoundingBox{}, false
+	}
+
+	result, _ := raw.(map[string]interface{})
+	model, _ := result["model"].(map[string]interface{})
+	quad, _ := model["border"].([]interface{})
+
+	if len(quad) < 8 {
+		return entity.BoundingBox{}, false
+	}
+
+	x1, _ := quad[0].(float64)
+	y1, _ := quad[1].(float64)
+	x3, _ := quad[4].(float64)
+	y3, _ := quad[5].(float64)
+
+	if x3-x1 <= 0 || y3-y1 <= 0 {
+		return entity.BoundingBox{}, false
+	}
+
+	return entity.BoundingBox{
+		X:      (x1 + x3) / 2,
+		Y:      (y1 + y3) / 2,
+		Width:  x3 - x1,
+		Height: y3 - y1,
+	}, true
+}
+
+// parentHasName skips text nodes that only repeat their parent's name.
+func parentHasName(out []entity.AXNode, depth int, name string) bool {
+	for i := len(out) - 1; i >= 0; i-- {
+		if out[i].Depth < depth {
+			return strings.Contains(out[i].Name, truncateName(name))
+		}
+	}
+
+	return false
+}
+
+func truncateName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+
+	if runes := []rune(name); len(runes) > maxAXName {
+		return string(runes[:maxAXName]) + "..."
+	}
+
+	return name
+}
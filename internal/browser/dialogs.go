@@ -0,0 +1,218 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	DialogAccept  = "accept"
+	DialogDismiss = "dismiss"
+	DialogAsk     = "ask"
+)
+
+type pendingDialog struct {
+	dialog playwright.Dialog
+	info   entity.Dialog
+}
+
+// onDialog queues a JavaScript dialog. The browser keeps the page blocked
+// until the dialog is answered, so alert and beforeunload are answered
+// according to config, while confirm and prompt wait for handle_dialog.
+func (m *Manager) onDialog(t *tab) func(playwright.Dialog) {
+	return func(dialog playwright.Dialog) {
+		info := entity.Dialog{
+			Type:         dialog.Type(),
+			Message:      dialog.Message(),
+			DefaultValue: dialog.DefaultValue(),
+			URL:          t.page.URL(),
+			TabID:        t.id,
+		}
+
+		m.logger.Info("Dialog opened",
+			zap.String("dialog_type", info.Type),
+			zap.String("dialog_message", info.Message),
+			zap.Int("tab_id", t.id))
+
+		if outcome := m.autoDialogOutcome(info.Type); outcome != DialogAsk {
+			go m.autoAnswerDialog(&pendingDialog{dialog: dialog, info: info}, outcome == DialogAccept)
+
+			return
+		}
+
+		m.dialogMu.Lock()
+		m.pendingDialogs = append(m.pendingDialogs, &pendingDialog{dialog: dialog, info: info})
+		close(m.dialogOpened)
+		m.dialogOpened = make(chan struct{})
+		m.dialogMu.Unlock()
+	}
+}
+
+func (m *Manager) autoDialogOutcome(dialogType string) string {
+	switch dialogType {
+	case "beforeunload":
+		return m.config.BrowserConfig.DialogBeforeUnload
+	case "alert":
+		return m.config.BrowserConfig.DialogAlert
+	default:
+		return DialogAsk
+	}
+}
+
+func (m *Manager) answerDialog(pending *pendingDialog, accept bool, promptText string) error {
+	var err error
+
+	if accept {
+		pending.info.Outcome = "accepted"
+
+		if pending.info.Type == "prompt" {
+			err = pending.dialog.Accept(promptText)
+		} else {
+			err = pending.dialog.Accept()
+		}
+	} else {
+		pending.info.Outcome = "dismissed"
+		err = pending.dialog.Dismiss()
+	}
+
+	return err
+}
+
+func (m *Manager) autoAnswerDialog(pending *pendingDialog, accept bool) {
+	if err := m.answerDialog(pending, accept, ""); err != nil {
+		m.logger.Warn("Failed to answer dialog", zap.Error(err))
+
+		return
+	}
+
+	m.dialogMu.Lock()
+	m.dialogLog = append(m.dialogLog, pending.info)
+	m.dialogMu.Unlock()
+}
+
+// untilDialog runs a page interaction but returns as soon as it opens a
+// dialog, because Playwright stalls the interaction until the dialog is
+// answered.
+func (m *Manager) untilDialog(fn func() error) error {
+	m.dialogMu.Lock()
+	opened := m.dialogOpened
+	m.dialogMu.Unlock()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-opened:
+		m.logger.Info("Interaction opened a dialog")
+
+		return nil
+	}
+}
+
+func (m *Manager) pendingDialogOnActivePage() bool {
+	m.dialogMu.Lock()
+	defer m.dialogMu.Unlock()
+
+	for _, pending := range m.pendingDialogs {
+		if pending.dialog.Page() == m.page {
+			return true
+		}
+	}
+
+	return false
+}
+
+// currentDialogs returns the dialogs still waiting for an answer followed by
+// the auto-answered ones not acknowledged yet. Reading page state does not
+// consume them, so a state taken only for bookkeeping loses nothing.
+func (m *Manager) currentDialogs() []entity.Dialog {
+	m.dialogMu.Lock()
+	defer m.dialogMu.Unlock()
+
+	dialogs := make([]entity.Dialog, 0, len(m.pendingDialogs)+len(m.dialogLog))
+
+	for _, pending := range m.pendingDialogs {
+		dialogs = append(dialogs, pending.info)
+	}
+
+	return append(dialogs, m.dialogLog...)
+}
+
+// AckDialogs drops answered dialogs from the log once they have been
+// reported. Pending dialogs stay until they are handled.
+func (m *Manager) AckDialogs(dialogs []entity.Dialog) {
+	m.dialogMu.Lock()
+	defer m.dialogMu.Unlock()
+
+	for _, dialog := range dialogs {
+		if i := slices.Index(m.dialogLog, dialog); i >= 0 && dialog.Outcome != "" {
+			m.dialogLog = slices.Delete(m.dialogLog, i, i+1)
+		}
+	}
+}
+
+func (m *Manager) PendingDialog() *entity.Dialog {
+	m.dialogMu.Lock()
+	defer m.dialogMu.Unlock()
+
+	if len(m.pendingDialogs) == 0 {
+		return nil
+	}
+
+	info := m.pendingDialogs[0].info
+
+	return &info
+}
+
+func (m *Manager) HandleDialog(ctx context.Context, accept bool, promptText string) (dialog *entity.Dialog, err error) {
+	const op = "HandleDialog"
+	logger := m.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.Bool("accept", accept))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return nil, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	m.dialogMu.Lock()
+	if len(m.pendingDialogs) == 0 {
+		m.dialogMu.Unlock()
+
+		return nil, apperr.NotFoundError(op, fmt.Errorf("no dialog is open"))
+	}
+
+	pending := m.pendingDialogs[0]
+	m.pendingDialogs = m.pendingDialogs[1:]
+	m.dialogMu.Unlock()
+
+	step.AddEvent("answering dialog", attribute.String("dialog_type", pending.info.Type))
+
+	if err := m.answerDialog(pending, accept, promptText); err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "dialog_answer_failed",
+			apperr.MetaStage:  apperr.StageInteraction,
+		})
+	}
+
+	m.settle(step)
+
+	return &pending.info, nil
+}
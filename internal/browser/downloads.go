@@ -0,0 +1,128 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"go.uber.org/zap"
+)
+
+const downloadWait = 15 * time.Second
+
+var unsafeFileChars = regexp.MustCompile(`[^\p{L}\p{N}._ -]+`)
+
+// SetDownloadDir sets where downloads are saved, normally one directory per task.
+func (m *Manager) SetDownloadDir(dir string) {
+	m.downloadMu.Lock()
+	m.downloadDir = dir
+	m.downloadMu.Unlock()
+}
+
+func (m *Manager) onDownload(download playwright.Download) {
+	m.downloadsInFlight.Add(1)
+
+	go func() {
+		defer m.downloadsInFlight.Add(-1)
+
+		record := m.saveDownload(download)
+
+		m.downloadMu.Lock()
+		m.downloads = append(m.downloads, record)
+		m.downloadMu.Unlock()
+	}()
+}
+
+func (m *Manager) saveDownload(download playwright.Download) entity.Download {
+	m.downloadMu.Lock()
+	dir := m.downloadDir
+	m.downloadMu.Unlock()
+
+	record := entity.Download{
+		Name: sanitizeFileName(download.SuggestedFilename()),
+		URL:  download.URL(),
+	}
+
+	logger := m.logger.With(zap.String("file_name", record.Name))
+	logger.Info("Download started")
+
+	if dir == "" {
+		record.Error = "downloads directory is not configured"
+		_ = download.Cancel()
+
+		return record
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		record.Error = err.Error()
+		_ = download.Cancel()
+
+		return record
+	}
+
+	record.Path = uniquePath(dir, record.Name)
+	record.Name = filepath.Base(record.Path)
+
+	if err := download.SaveAs(record.Path); err != nil {
+		logger.Warn("Download failed", zap.Error(err))
+		record.Error = err.Error()
+
+		return record
+	}
+
+	if info, err := os.Stat(record.Path); err == nil {
+		record.Size = info.Size()
+	}
+
+	logger.Info("Download saved", zap.String("path", record.Path), zap.Int64("size", record.Size))
+
+	return record
+}
+
+// DrainDownloads waits for in-progress downloads and returns the ones
+// finished since the previous call.
+func (m *Manager) DrainDownloads() []entity.Download {
+	deadline := time.Now().Add(downloadWait)
+
+	for m.downloadsInFlight.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	m.downloadMu.Lock()
+	defer m.downloadMu.Unlock()
+
+	downloads := m.downloads
+	m.downloads = nil
+
+	return downloads
+}
+
+func sanitizeFileName(name string) string {
+	name = unsafeFileChars.ReplaceAllString(filepath.Base(name), "_")
+	name = strings.Trim(name, ". ")
+
+	if name == "" {
+		return "download"
+	}
+
+	return name
+}
+
+func uniquePath(dir, name string) string {
+	path := filepath.Join(dir, name)
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	for i := 1; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+
+		path = filepath.Join(dir, fmt.Sprintf("%s (%d)%s", base, i, ext))
+	}
+}
@@ -1,11 +1,87 @@
 package browser
 
+// elementRefsJS declares refs and refOf. Element ids live as long as the
+// document, so the same element keeps its id across snapshots. The doc token
+// detects navigations.
+const elementRefsJS = `const refs = window.__agentRefs || (window.__agentRefs = {
+				doc: Math.random().toString(36).slice(2),
+				next: 1,
+				ids: new WeakMap(),
+				els: new Map()
+			});
+			const refOf = (el) => {
+				let id = refs.ids.get(el);
+				if (!id) {
+					id = refs.next++;
+					refs.ids.set(el, id);
+					refs.els.set(id, new WeakRef(el));
+				}
+				return id;
+			};`
+
+// frameSelectorJS declares frameSelector, which builds the selector of an
+// iframe element for " >>> " frame chains.
+const frameSelectorJS = `const frameSelector = (frame) => {
+				const tag = frame.tagName.toLowerCase();
+				if (frame.id && /^[a-zA-Z][\w-]*$/.test(frame.id)) return '#' + frame.id;
+				const name = frame.getAttribute('name');
+				if (name) return tag + '[name="' + name + '"]';
+				const title = frame.getAttribute('title');
+				if (title && title.length < 80) return tag + '[title="' + title + '"]';
+				const src = frame.getAttribute('src');
+				if (src && src.length < 120 && !src.startsWith('data:')) return tag + '[src="' + src + '"]';
+				const siblings = Array.from(frame.parentNode?.children || []).filter(c => c.tagName === frame.tagName);
+				return tag + ':nth-of-type(' + (siblings.indexOf(frame) + 1) + ')';
+			};`
+
+// sensitiveInputJS declares isSensitive, true for inputs whose value must
+// never leave the page: passwords, payment and one-time-code fields.
+const sensitiveInputJS = `const isSensitive = (el) => {
+				if (!el || el.tagName !== 'INPUT') return false;
+				if (el.type === 'password' || el.hasAttribute('data-sensitive')) return true;
+				const auto = (el.getAttribute('autocomplete') || '').toLowerCase();
+				if (auto.startsWith('cc-') || auto === 'one-time-code' || auto.endsWith('password')) return true;
+				const hint = ((el.getAttribute('name') || '') + ' ' + (el.id || '')).toLowerCase();
+				return /(^|[^a-z])(password|passwd|pin|cvv|cvc|csc|card(number|num|no)?|cc|otp)([^a-z]|$)/.test(hint);
+			};`
+
 func getElementsScript() string {
 	return `(() => {
 		try {
 			const result = [];
 			const seen = new Set();
-			const all = document.querySelectorAll('*');
+			
+			` + elementRefsJS + `
+			` + sensitiveInputJS + `
+			const all = [];
+			
+			` + frameSelectorJS + `
+			
+			// Walk open shadow roots and same-origin iframes. Frame offsets turn
+			// frame-relative rects into page coordinates.
+			const collect = (root, frames, offX, offY) => {
+				for (const el of root.querySelectorAll('*')) {
+					all.push({el, frames, offX, offY});
+					
+					if (el.shadowRoot) {
+						collect(el.shadowRoot, frames, offX, offY);
+					}
+					
+					const tag = el.tagName.toLowerCase();
+					if (tag !== 'iframe' && tag !== 'frame') continue;
+					
+					let doc = null;
+					try { doc = el.contentDocument; } catch (e) {}
+					if (!doc || !doc.documentElement) continue;
+					
+					const r = el.getBoundingClientRect();
+					if (r.width === 0 || r.height === 0) continue;
+					
+					collect(doc, frames.concat([frameSelector(el)]), offX + r.left + el.clientLeft, offY + r.top + el.clientTop);
+				}
+			};
+			
+			collect(document, [], 0, 0);
 			
 			const priorityTags = ['a', 'button', 'input', 'select', 'textarea'];
 			const secondaryTags = ['h1', 'h2', 'h3', 'h4', 'h5', 'h6', 'span', 'div', 'label'];
@@ -127,14 +203,22 @@ func getElementsScript() string {
 				tags.forEach(targetTag => {
 					let count = 0;
 					for (let i = 0; i < all.length && count < maxPerTag; i++) {
-						const el = all[i];
+						const entry = all[i];
+						const el = entry.el;
 						const tag = el.tagName.toLowerCase();
 						
 						if (tag !== targetTag) continue;
 						if (seen.has(el)) continue;
 						
-						const rect = el.getBoundingClientRect();
-						const style = window.getComputedStyle(el);
+						const inner = el.getBoundingClientRect();
+						const rect = {
+							left: inner.left + entry.offX,
+							top: inner.top + entry.offY,
+							bottom: inner.bottom + entry.offY,
+							width: inner.width,
+							height: inner.height
+						};
+						const style = el.ownerDocument.defaultView.getComputedStyle(el);
 						
 						const isVisible = (
 							rect.width > 0 && 
@@ -156,7 +240,7 @@ func getElementsScript() string {
 						const role = el.getAttribute('role');
 						
 						let txt = '';
-						if (el.value) {
+						if (el.value && !isSensitive(el)) {
 						 txt = el.value;
 						} else if (el.innerText && el.innerText.trim()) {
 						 txt = el.innerText;
@@ -176,7 +260,7 @@ func getElementsScript() string {
 							txt = txt.substring(0, 200) + '...';
 						}
 						
-						const sel = generateSelector(el);
+						const sel = entry.frames.concat([generateSelector(el)]).join(' >>> ');
 						
 						const attrs = {};
 						if (el.type) attrs.type = el.type;
@@ -230,6 +314,7 @@ func getElementsScript() string {
 						const centerY = Math.round(rect.top + rect.height / 2);
 						
 						result.push({
+							id: refOf(el),
 							tag: tag,
 							text: txt,
 							selector: sel,
@@ -248,10 +333,393 @@ func getElementsScript() string {
 			processByPriority(priorityTags, 50);
 			processByPriority(secondaryTags, 20);
 			
-			return result;
+			return {doc: refs.doc, elements: result};
 		} catch(e) {
 			console.error('Error in GetElements:', e);
-			return [];
+			return {doc: '', elements: []};
 		}
 	})()`
 }
+
+// resolveRefScript marks the element behind an id so a selector can reach it,
+// or reports it stale when it was removed or the page navigated.
+func resolveRefScript() string {
+	return `(arg) => {
+		const refs = window.__agentRefs;
+		if (!refs || refs.doc !== arg.doc) return {stale: 'the page has changed since the element list was taken'};
+
+		const ref = refs.els.get(arg.id);
+		const el = ref && ref.deref();
+		if (!el || !el.isConnected) return {stale: 'the element was removed from the page'};
+
+		el.setAttribute('data-agent-ref', String(arg.id));
+
+		return {stale: ''};
+	}`
+}
+
+// describeElementScript is evaluated on an element handle, so it works for
+// elements inside iframes and shadow roots alike.
+func describeElementScript() string {
+	return `(el) => {
+		if (!el) return null;
+
+		` + sensitiveInputJS + `
+
+		const attrs = {};
+		for (const name of ['id', 'name', 'type', 'placeholder', 'aria-label', 'autocomplete', 'role', 'href', 'title', 'class', 'data-qa', 'data-test-id', 'data-testid']) {
+			const val = el.getAttribute(name);
+			if (val) attrs[name] = val.substring(0, 200);
+		}
+
+		const value = isSensitive(el) ? '' : el.value;
+		let text = (el.innerText || el.textContent || value || el.getAttribute('aria-label') || '').trim();
+		if (text.length > 200) text = text.substring(0, 200);
+
+		const rect = el.getBoundingClientRect();
+
+		return {
+			tag: el.tagName.toLowerCase(),
+			text: text,
+			attributes: attrs,
+			x: Math.round(rect.left + rect.width / 2),
+			y: Math.round(rect.top + rect.height / 2),
+			width: Math.round(rect.width),
+			height: Math.round(rect.height)
+		};
+	}`
+}
+
+// elementAtScript finds the interactive element at page coordinates,
+// descending into open shadow roots and same-origin iframes.
+func elementAtScript() string {
+	return `(arg) => {
+		let doc = document;
+		let x = arg.x, y = arg.y, offX = 0, offY = 0;
+		let el = doc.elementFromPoint(x, y);
+
+		for (let depth = 0; el && depth < 10; depth++) {
+			if (el.shadowRoot) {
+				const inner = el.shadowRoot.elementFromPoint(x, y);
+				if (inner && inner !== el) { el = inner; continue; }
+			}
+
+			const tag = el.tagName.toLowerCase();
+			if (tag !== 'iframe' && tag !== 'frame') break;
+
+			let inner = null;
+			try { inner = el.contentDocument; } catch (e) {}
+			if (!inner) break;
+
+			const r = el.getBoundingClientRect();
+			x -= r.left + el.clientLeft;
+			y -= r.top + el.clientTop;
+			offX += r.left + el.clientLeft;
+			offY += r.top + el.clientTop;
+			doc = inner;
+			el = doc.elementFromPoint(x, y);
+		}
+
+		if (!el) return null;
+
+		const interactive = el.closest('a, button, input, select, textarea, label, [role="button"], [role="link"], [role="menuitem"], [onclick]');
+		if (interactive) el = interactive;
+
+		const described = (` + describeElementScript() + `)(el);
+		described.x += Math.round(offX);
+		described.y += Math.round(offY);
+
+		return described;
+	}`
+}
+
+// marksScript draws numbered boxes over the listed elements. Coordinates are
+// element centers, as in the element list.
+func marksScript() string {
+	return `(arg) => {
+		document.querySelectorAll('[data-agent-marks]').forEach(n => n.remove());
+
+		const layer = document.createElement('div');
+		layer.setAttribute('data-agent-marks', '1');
+		layer.style.cssText = 'position:fixed;left:0;top:0;width:0;height:0;z-index:2147483647;pointer-events:none';
+
+		const colors = ['#ff2d55', '#007aff', '#34c759', '#ff9500', '#af52de', '#00a5a5'];
+
+		arg.marks.forEach((mark, i) => {
+			const left = mark.x - mark.width / 2;
+			const top = mark.y - mark.height / 2;
+			if (top + mark.height < 0 || top > window.innerHeight || left + mark.width < 0 || left > window.innerWidth) return;
+
+			const color = colors[i % colors.length];
+
+			const box = document.createElement('div');
+			box.style.cssText = [
+				'position:fixed',
+				'left:' + left + 'px',
+				'top:' + top + 'px',
+				'width:' + Math.max(mark.width, 8) + 'px',
+				'height:' + Math.max(mark.height, 8) + 'px',
+				'border:2px solid ' + color,
+				'box-sizing:border-box'
+			].join(';');
+
+			const label = document.createElement('div');
+			label.textContent = String(mark.id);
+			label.style.cssText = 'position:absolute;left:-2px;top:' + (top >= 16 ? '-16px' : '0') + ';background:' + color + ';color:#fff;font:bold 11px/14px monospace;padding:0 3px;white-space:nowrap';
+			box.appendChild(label);
+
+			layer.appendChild(box);
+		});
+
+		document.documentElement.appendChild(layer);
+
+		return true;
+	}`
+}
+
+func removeMarksScript() string {
+	return `() => {
+		document.querySelectorAll('[data-agent-marks]').forEach(n => n.remove());
+		return true;
+	}`
+}
+
+func highlightScript() string {
+	return `(arg) => {
+		document.querySelectorAll('[data-agent-highlight]').forEach(n => n.remove());
+
+		const box = document.createElement('div');
+		box.setAttribute('data-agent-highlight', '1');
+		box.style.cssText = [
+			'position:fixed',
+			'left:' + (arg.x - arg.width / 2) + 'px',
+			'top:' + (arg.y - arg.height / 2) + 'px',
+			'width:' + Math.max(arg.width, 12) + 'px',
+			'height:' + Math.max(arg.height, 12) + 'px',
+			'border:3px dashed #ff2d55',
+			'background:rgba(255,45,85,0.15)',
+			'z-index:2147483647',
+			'pointer-events:none',
+			'box-sizing:border-box'
+		].join(';');
+
+		const label = document.createElement('div');
+		label.textContent = arg.label;
+		label.style.cssText = 'position:absolute;left:0;top:-22px;background:#ff2d55;color:#fff;font:12px/18px monospace;padding:0 6px;white-space:nowrap';
+		box.appendChild(label);
+
+		document.documentElement.appendChild(box);
+		setTimeout(() => box.remove(), arg.ttl);
+
+		return true;
+	}`
+}
+
+// extractDataScript returns repeated records below el as rows of named
+// fields. Tables use their header cells as field names. Other lists are
+// found by grouping sibling elements with the same tag and class; field
+// names come from itemprop, data-qa/testid or BEM-style class names of the
+// text-bearing elements inside each record.
+func extractDataScript() string {
+	return `(el, arg) => {
+		const clean = (s) => (s || '').replace(/\s+/g, ' ').trim();
+		const slug = (s) => clean(s).toLowerCase().replace(/[^\p{L}\p{N}]+/gu, '_').replace(/^_+|_+$/g, '').slice(0, 40);
+
+		const visible = (node) => {
+			const r = node.getBoundingClientRect();
+			if (r.width === 0 && r.height === 0) return false;
+			const style = node.ownerDocument.defaultView.getComputedStyle(node);
+			return style.display !== 'none' && style.visibility !== 'hidden';
+		};
+
+		const uniqueNames = (names) => {
+			const used = {};
+			return names.map((name, i) => {
+				let n = name || 'column_' + (i + 1);
+				if (used[n]) n = n + '_' + (++used[n]);
+				else used[n] = 1;
+				return n;
+			});
+		};
+
+		const fromTable = (table) => {
+			const rows = Array.from(table.rows).filter(visible);
+			if (!rows.length) return null;
+
+			let header = null;
+			if (table.tHead && table.tHead.rows.length) {
+				header = table.tHead.rows[table.tHead.rows.length - 1];
+			} else if (Array.from(rows[0].cells).every(c => c.tagName === 'TH')) {
+				header = rows[0];
+			}
+
+			const headerCells = header ? Array.from(header.cells) : [];
+			const fields = uniqueNames(headerCells.map(c => slug(c.innerText)));
+			const records = [];
+
+			rows.filter(r => r !== header && !(table.tHead && table.tHead.contains(r))).forEach(r => {
+				const cells = Array.from(r.cells);
+				if (!cells.some(c => c.tagName === 'TD')) return;
+
+				const record = {};
+				cells.forEach((cell, i) => {
+					const name = fields[i] || 'column_' + (i + 1);
+					if (!fields[i]) fields[i] = name;
+					record[name] = clean(cell.innerText);
+
+					const links = cell.querySelectorAll('a[href]');
+					if (links.length === 1 && !links[0].href.startsWith('javascript:')) {
+						record[name + '_url'] = links[0].href;
+					}
+				});
+				records.push(record);
+			});
+
+			return {source: 'table', records: records};
+		};
+
+		const signature = (node) => {
+			const cls = typeof node.className === 'string' ? node.className.trim().split(/\s+/)[0] || '' : '';
+			return node.tagName + '.' + cls;
+		};
+
+		// Finds the largest group of same-signature children under node.
+		const repeatedChildren = (node) => {
+			const groups = new Map();
+			for (const child of node.children) {
+				if (!visible(child)) continue;
+				const sig = signature(child);
+				if (!groups.has(sig)) groups.set(sig, []);
+				groups.get(sig).push(child);
+			}
+
+			let best = null;
+			groups.forEach(items => {
+				if (items.length < 3) return;
+				const text = items.reduce((sum, i) => sum + clean(i.innerText).length, 0) / items.length;
+				if (text < 10) return;
+				const score = items.length * Math.min(text, 300);
+				if (!best || score > best.score) best = {items: items, score: score};
+			});
+
+			return best;
+		};
+
+		const fieldName = (node, item) => {
+			for (let cur = node; cur && cur !== item; cur = cur.parentElement) {
+				const prop = cur.getAttribute('itemprop');
+				if (prop) return slug(prop);
+
+				const qa = cur.getAttribute('data-qa') || cur.getAttribute('data-testid') || cur.getAttribute('data-test-id');
+				if (qa) {
+					const parts = qa.split(/__|--/);
+					return slug(parts[parts.length - 1]);
+				}
+
+				if (typeof cur.className === 'string') {
+					for (const c of cur.className.trim().split(/\s+/)) {
+						if (!c || /[0-9a-f]{6,}|^css-|^sc-|^jsx-/i.test(c)) continue;
+						const parts = c.split(/__|--/);
+						const name = slug(parts[parts.length - 1]);
+						if (name && name.length > 1) return name;
+					}
+				}
+			}
+
+			return node.tagName === 'A' ? 'link' : node.tagName === 'IMG' ? 'image' : '';
+		};
+
+		const fromRecords = (items) => {
+			const records = items.map(item => {
+				const record = {};
+				const add = (name, value) => {
+					if (!value) return;
+					let key = name || 'text';
+					let n = 1;
+					while (record[key] !== undefined) key = (name || 'text') + '_' + (++n);
+					record[key] = value;
+				};
+
+				const walker = item.ownerDocument.createTreeWalker(item, NodeFilter.SHOW_ELEMENT);
+				const leaves = [];
+				for (let node = walker.currentNode; node; node = walker.nextNode()) {
+					if (['SCRIPT', 'STYLE', 'SVG', 'NOSCRIPT'].includes(node.tagName.toUpperCase())) continue;
+					if (node.tagName === 'IMG') {
+						leaves.push(node);
+						continue;
+					}
+					const own = Array.from(node.childNodes).some(c => c.nodeType === Node.TEXT_NODE && clean(c.textContent));
+					if (own && visible(node)) leaves.push(node);
+				}
+
+				leaves.forEach(node => {
+					if (node.tagName === 'IMG') {
+						add(fieldName(node, item) || 'image', node.currentSrc || node.src);
+						return;
+					}
+					const name = fieldName(node, item);
+					add(name, clean(node.innerText));
+
+					const link = node.closest('a[href]');
+					if (link && item.contains(link) && !link.href.startsWith('javascript:')) {
+						const key = (name || 'link') + '_url';
+						if (record[key] === undefined) record[key] = link.href;
+					}
+				});
+
+				return record;
+			});
+
+			// Drop fields that only appear in a few records, they are usually
+			// badges or ads rather than part of the record structure.
+			const counts = {};
+			records.forEach(r => Object.keys(r).forEach(k => { counts[k] = (counts[k] || 0) + 1; }));
+			const keep = Object.keys(counts)
+				.filter(k => counts[k] >= Math.max(1, Math.ceil(records.length / 3)))
+				.slice(0, 20);
+
+			return {
+				source: 'list',
+				records: records.map(r => {
+					const out = {};
+					keep.forEach(k => { if (r[k] !== undefined) out[k] = r[k]; });
+					return out;
+				})
+			};
+		};
+
+		const detect = (root) => {
+			if (root.tagName === 'TABLE') return fromTable(root);
+
+			const tables = Array.from(root.querySelectorAll('table'))
+				.filter(t => visible(t) && t.rows.length >= 2)
+				.sort((a, b) => b.rows.length - a.rows.length);
+
+			let best = null;
+			for (const node of [root, ...root.querySelectorAll('*')]) {
+				if (!node.children || node.children.length < 3) continue;
+				const group = repeatedChildren(node);
+				if (group && (!best || group.score > best.score)) best = group;
+			}
+
+			if (tables.length && (!best || tables[0].rows.length >= best.items.length)) {
+				return fromTable(tables[0]);
+			}
+
+			return best ? fromRecords(best.items) : null;
+		};
+
+		const result = detect(el);
+		if (!result) return {source: '', fields: [], rows: [], total: 0};
+
+		const fields = [];
+		result.records.forEach(r => Object.keys(r).forEach(k => { if (!fields.includes(k)) fields.push(k); }));
+
+		return {
+			source: result.source,
+			fields: fields,
+			rows: result.records.slice(0, arg.limit),
+			total: result.records.length
+		};
+	}`
+}
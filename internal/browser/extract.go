@@ -0,0 +1,118 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultExtractLimit = 50
+	maxExtractLimit     = 200
+)
+
+// ExtractData returns the repeated records (table rows, list items, cards)
+// inside selector, or the most prominent ones on the page when selector is
+// empty.
+func (m *Manager) ExtractData(ctx context.Context, selector string, limit int) (data *entity.ExtractedData, err error) {
+	const op = "ExtractData"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("selector", selector),
+		attribute.Int("limit", limit))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return nil, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultExtractLimit
+	}
+
+	limit = min(limit, maxExtractLimit)
+
+	target := "body"
+	if selector != "" {
+		target = selector
+	}
+
+	step.AddEvent("extracting records")
+
+	result, err := m.locate(target).Evaluate(extractDataScript(), map[string]interface{}{
+		"limit": limit,
+	}, playwright.LocatorEvaluateOptions{
+		Timeout: playwright.Float(waitTimeout),
+	})
+	if err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeNotFound, err, map[string]any{
+			apperr.MetaReason:   "element_not_found",
+			apperr.MetaStage:    apperr.StagePageState,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	resultMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, apperr.Wrap(op, apperr.CodeInternal, fmt.Errorf("unexpected result type %T", result), map[string]any{
+			apperr.MetaReason: "invalid_result",
+		})
+	}
+
+	data = &entity.ExtractedData{
+		Source: getString(resultMap, "source"),
+		Total:  int(getFloat(resultMap, "total")),
+	}
+
+	if fields, ok := resultMap["fields"].([]interface{}); ok {
+		for _, field := range fields {
+			if name, ok := field.(string); ok {
+				data.Fields = append(data.Fields, name)
+			}
+		}
+	}
+
+	if rows, ok := resultMap["rows"].([]interface{}); ok {
+		for _, row := range rows {
+			rowMap, ok := row.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			record := make(map[string]string, len(rowMap))
+			for key := range rowMap {
+				record[key] = getString(rowMap, key)
+			}
+
+			data.Rows = append(data.Rows, record)
+		}
+	}
+
+	step.AddEvent("records extracted",
+		attribute.String("source", data.Source),
+		attribute.Int("rows", len(data.Rows)),
+		attribute.Int("total", data.Total))
+
+	return data, nil
+}
@@ -0,0 +1,237 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const maxTextMatches = 20
+
+// FindText searches the whole document, including offscreen content, open
+// shadow roots and same-origin iframes. Matches get element ids like the
+// element list, so the agent can click them right away. With scroll the best
+// match is scrolled to the middle of the viewport.
+func (m *Manager) FindText(ctx context.Context, query string, exact, scroll bool) (search *entity.TextSearch, err error) {
+	const op = "FindText"
+	logger := m.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.Bool("exact", exact),
+		attribute.Bool("scroll", scroll))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return nil, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	if strings.TrimSpace(query) == "" {
+		return nil, apperr.InvalidReqError(op, "query", fmt.Errorf("query cannot be empty"))
+	}
+
+	step.AddEvent("searching text")
+
+	result, err := m.page.Evaluate(findTextScript(), map[string]interface{}{
+		"query":  query,
+		"exact":  exact,
+		"scroll": scroll,
+		"limit":  maxTextMatches,
+	})
+	if err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "evaluate_failed",
+		})
+	}
+
+	resultMap, _ := result.(map[string]interface{})
+
+	search = &entity.TextSearch{
+		Query:    query,
+		Total:    int(getFloat(resultMap, "total")),
+		Scrolled: getBool(resultMap, "scrolled"),
+	}
+
+	var elements []entity.Element
+
+	if matches, ok := resultMap["matches"].([]interface{}); ok {
+		for _, match := range matches {
+			matchMap, ok := match.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			elem := parseElement(matchMap)
+			elements = append(elements, elem)
+
+			search.Matches = append(search.Matches, entity.TextMatch{
+				Element:    elem,
+				Context:    getString(matchMap, "context"),
+				InViewport: getBool(matchMap, "inViewport"),
+			})
+		}
+	}
+
+	m.addElements(getString(resultMap, "doc"), elements)
+
+	if search.Scrolled {
+		m.settle(step)
+	}
+
+	step.AddEvent("search completed",
+		attribute.Int("total", search.Total),
+		attribute.Bool("scrolled", search.Scrolled))
+
+	return search, nil
+}
+
+func findTextScript() string {
+	return `(arg) => {
+		` + elementRefsJS + `
+		` + frameSelectorJS + `
+		` + sensitiveInputJS + `
+
+		const norm = (s) => (s || '').replace(/\s+/g, ' ').trim();
+		const query = arg.exact ? norm(arg.query) : norm(arg.query).toLowerCase();
+		const whole = norm(arg.query).toLowerCase();
+		const matchesText = (text) => {
+			const t = arg.exact ? norm(text) : norm(text).toLowerCase();
+			return arg.exact ? t === query : t.includes(query);
+		};
+		const contains = (text) => {
+			const t = arg.exact ? norm(text) : norm(text).toLowerCase();
+			return t.includes(query);
+		};
+
+		const interactive = 'a, button, input, select, textarea, label, summary, [role="button"], [role="link"], [role="tab"], [role="menuitem"], [role="option"], [onclick]';
+
+		// Roots to search: the document, open shadow roots and same-origin
+		// iframes, with the frame chain and offsets of each.
+		const roots = [];
+		const addRoots = (root, frames, offX, offY) => {
+			roots.push({root, frames, offX, offY});
+			for (const el of root.querySelectorAll('*')) {
+				if (el.shadowRoot) addRoots(el.shadowRoot, frames, offX, offY);
+
+				const tag = el.tagName.toLowerCase();
+				if (tag !== 'iframe' && tag !== 'frame') continue;
+
+				let doc = null;
+				try { doc = el.contentDocument; } catch (e) {}
+				if (!doc || !doc.documentElement) continue;
+
+				const r = el.getBoundingClientRect();
+				addRoots(doc, frames.concat([frameSelector(el)]), offX + r.left + el.clientLeft, offY + r.top + el.clientTop);
+			}
+		};
+		addRoots(document, [], 0, 0);
+
+		const found = [];
+		const seen = new Set();
+
+		const visible = (el) => {
+			const style = el.ownerDocument.defaultView.getComputedStyle(el);
+			if (style.display === 'none' || style.visibility === 'hidden') return false;
+			const r = el.getBoundingClientRect();
+			return r.width > 0 || r.height > 0;
+		};
+
+		const addMatch = (el, entry) => {
+			if (!el || seen.has(el) || !visible(el)) return;
+			seen.add(el);
+			found.push({el, entry});
+		};
+
+		for (const entry of roots) {
+			const doc = entry.root.ownerDocument || entry.root;
+			const walker = doc.createTreeWalker(entry.root, NodeFilter.SHOW_TEXT);
+			for (let node = walker.nextNode(); node; node = walker.nextNode()) {
+				const parent = node.parentElement;
+				if (!parent || ['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE'].includes(parent.tagName)) continue;
+				if (contains(node.textContent) && matchesText(arg.exact ? parent.innerText || node.textContent : node.textContent)) {
+					addMatch(parent, entry);
+				}
+			}
+
+			for (const el of entry.root.querySelectorAll('input[type="submit"], input[type="button"], [aria-label], [placeholder], [title]')) {
+				const label = (isSensitive(el) ? '' : el.value) || el.getAttribute('aria-label') || el.getAttribute('placeholder') || el.getAttribute('title');
+				if (matchesText(label)) addMatch(el, entry);
+			}
+		}
+
+		// Text split across inline tags ("Add <b>to</b> cart") has no single
+		// text node with the whole query, so fall back to the smallest
+		// elements whose text contains it.
+		if (!found.length) {
+			for (const entry of roots) {
+				for (const el of entry.root.querySelectorAll('*')) {
+					const text = el.textContent || '';
+					if (text.length > 2000 || !matchesText(el.innerText || text)) continue;
+					const child = Array.from(el.children).some(c => matchesText(c.innerText || c.textContent));
+					if (!child) addMatch(el, entry);
+				}
+			}
+		}
+
+		const describe = ({el, entry}) => {
+			const target = el.closest(interactive) || el;
+			const r = target.getBoundingClientRect();
+			const left = r.left + entry.offX;
+			const top = r.top + entry.offY;
+
+			const block = el.closest('p, li, td, th, dd, article, section, div') || el;
+			let context = norm(block.innerText || block.textContent);
+			if (context.length > 200) {
+				const at = Math.max(0, context.toLowerCase().indexOf(whole) - 80);
+				context = (at > 0 ? '...' : '') + context.slice(at, at + 200) + '...';
+			}
+
+			return {
+				id: refOf(target),
+				tag: target.tagName.toLowerCase(),
+				text: norm(target.innerText || (isSensitive(target) ? '' : target.value) || target.getAttribute('aria-label') || '').slice(0, 120),
+				selector: entry.frames.concat(['*']).join(' >>> '),
+				visible: true,
+				clickable: target !== el || target.matches(interactive),
+				x: Math.round(left + r.width / 2),
+				y: Math.round(top + r.height / 2),
+				width: Math.round(r.width),
+				height: Math.round(r.height),
+				context: context,
+				inViewport: top + r.height > 0 && top < window.innerHeight && left + r.width > 0 && left < window.innerWidth
+			};
+		};
+
+		// Best match first: exact text, then clickable, then page order.
+		const rank = (m) => (norm(m.el.innerText || m.el.textContent).toLowerCase() === whole ? 0 : 2) + (m.el.closest(interactive) ? 0 : 1);
+		const ordered = found.map((m, i) => ({m, i, r: rank(m)})).sort((a, b) => a.r - b.r || a.i - b.i).map(x => x.m);
+
+		let scrolled = false;
+		if (arg.scroll && ordered.length) {
+			const best = ordered[0].el.closest(interactive) || ordered[0].el;
+			best.scrollIntoView({behavior: 'instant', block: 'center', inline: 'center'});
+			scrolled = true;
+		}
+
+		return {
+			doc: refs.doc,
+			total: ordered.length,
+			scrolled: scrolled,
+			matches: ordered.slice(0, arg.limit).map(describe)
+		};
+	}`
+}
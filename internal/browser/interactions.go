@@ -0,0 +1,474 @@
+package browser
+
+import (
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const isNativeSelectScript = `el => el.tagName === 'SELECT'`
+
+const selectedOptionsScript = `el => Array.from(el.selectedOptions).map(o => o.label || o.text).join(', ')`
+
+// SelectOption picks an option by its label or value. Native <select>
+// elements are set directly; custom dropdowns are opened by clicking selector
+// and the option is then clicked by its text, looked up only inside the popup
+// that opened. It returns the label that ended up selected.
+func (m *Manager) SelectOption(ctx context.Context, selector, option string) (selected string, err error) {
+	const op = "SelectOption"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("selector", selector))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return "", apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return "", apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return "", err
+	}
+
+	locator := m.locate(selector)
+
+	isNative, err := locator.Evaluate(isNativeSelectScript, nil, playwright.LocatorEvaluateOptions{
+		Timeout: playwright.Float(waitTimeout),
+	})
+	if err != nil {
+		return "", apperr.Wrap(op, apperr.CodeNotFound, err, map[string]any{
+			apperr.MetaReason:   "element_not_found",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	if ok, _ := isNative.(bool); ok {
+		step.AddEvent("selecting native option")
+
+		err = m.untilDialog(func() error {
+			_, err := locator.SelectOption(playwright.SelectOptionValues{
+				ValuesOrLabels: &[]string{option},
+			}, playwright.LocatorSelectOptionOptions{
+				Timeout: playwright.Float(clickTimeout),
+			})
+
+			return err
+		})
+		if err != nil {
+			return "", apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+				apperr.MetaReason:   "option_not_found",
+				apperr.MetaStage:    apperr.StageInteraction,
+				apperr.MetaSelector: selector,
+			})
+		}
+
+		m.settle(step)
+
+		labels, err := locator.Evaluate(selectedOptionsScript, nil)
+		if err != nil {
+			return option, nil
+		}
+
+		selected, _ = labels.(string)
+
+		return selected, nil
+	}
+
+	step.AddEvent("opening custom dropdown")
+
+	err = m.untilDialog(func() error {
+		return locator.Click(playwright.LocatorClickOptions{
+			Timeout: playwright.Float(clickTimeout),
+		})
+	})
+	if err != nil {
+		return "", apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "dropdown_open_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	m.settle(step)
+
+	step.AddEvent("finding option in the opened popup")
+
+	found, err := locator.Evaluate(popupOptionScript(), map[string]interface{}{
+		"option": option,
+	}, playwright.LocatorEvaluateOptions{
+		Timeout: playwright.Float(waitTimeout),
+	})
+	if err != nil {
+		return "", apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "option_lookup_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	foundMap, _ := found.(map[string]interface{})
+
+	token := getString(foundMap, "token")
+	if token == "" {
+		reason := fmt.Errorf("option %q not found in the opened dropdown", option)
+		if !getBool(foundMap, "popup") {
+			reason = fmt.Errorf("no listbox or menu opened after clicking the dropdown, click the option by its id instead")
+		}
+
+		return "", apperr.Wrap(op, apperr.CodeActionFailed, reason, map[string]any{
+			apperr.MetaReason:   "option_not_found",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	// The option lives in the dropdown's own frame, so only the last part of
+	// the frame chain is replaced.
+	parts := splitFrameSelector(selector)
+	parts[len(parts)-1] = fmt.Sprintf(`[data-agent-option="%s"]`, token)
+	target := m.locate(strings.Join(parts, frameSeparator))
+
+	step.AddEvent("clicking option")
+
+	err = m.untilDialog(func() error {
+		return target.Click(playwright.LocatorClickOptions{
+			Timeout: playwright.Float(clickTimeout),
+		})
+	})
+	if err != nil {
+		return "", apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "option_click_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	m.settle(step)
+
+	if text := getString(foundMap, "text"); text != "" {
+		return text, nil
+	}
+
+	return option, nil
+}
+
+// popupOptionScript looks for the option only inside the popup the dropdown
+// opened: the elements named by aria-controls or aria-owns on the dropdown
+// (or its combobox wrapper), else a visible listbox or menu in the same
+// document. The match is tagged with data-agent-option so it can be clicked.
+func popupOptionScript() string {
+	return `(el, arg) => {
+		const root = el.getRootNode();
+		const doc = el.ownerDocument;
+		const norm = (s) => (s || '').replace(/\s+/g, ' ').trim().toLowerCase();
+		const visible = (n) => {
+			const style = doc.defaultView.getComputedStyle(n);
+			if (style.display === 'none' || style.visibility === 'hidden') return false;
+			const r = n.getBoundingClientRect();
+			return r.width > 0 && r.height > 0;
+		};
+		const byId = (id) => (root.getElementById && root.getElementById(id)) || doc.getElementById(id);
+
+		const popups = [];
+		const owners = [el, el.closest('[role="combobox"], [aria-haspopup]'), el.querySelector('[aria-controls], [aria-owns]')];
+		for (const owner of owners) {
+			if (!owner) continue;
+			for (const attr of ['aria-controls', 'aria-owns']) {
+				for (const id of (owner.getAttribute(attr) || '').split(/\s+/)) {
+					const popup = id && byId(id);
+					if (popup && !popups.includes(popup)) popups.push(popup);
+				}
+			}
+		}
+		if (!popups.length) {
+			for (const popup of root.querySelectorAll('[role="listbox"], [role="menu"]')) {
+				if (visible(popup)) popups.push(popup);
+			}
+		}
+
+		const query = norm(arg.option);
+		let exact = null;
+		let partial = null;
+		for (const popup of popups) {
+			const items = popup.querySelectorAll('[role="option"], [role="menuitem"], [role="menuitemradio"], [role="menuitemcheckbox"], li');
+			for (const item of items) {
+				if (!visible(item)) continue;
+				const text = norm(item.getAttribute('aria-label') || item.innerText || item.textContent);
+				if (text === query) {
+					exact = item;
+					break;
+				}
+				if (!partial && text && text.includes(query)) partial = item;
+			}
+			if (exact) break;
+		}
+
+		const match = exact || partial;
+		if (!match) return {popup: popups.length > 0, token: ''};
+
+		const token = Math.random().toString(36).slice(2);
+		match.setAttribute('data-agent-option', token);
+
+		return {popup: true, token: token, text: (match.innerText || match.textContent || '').replace(/\s+/g, ' ').trim().slice(0, 200)};
+	}`
+}
+
+// SetChecked checks or unchecks a checkbox, radio button or switch and
+// verifies the resulting state. Custom widgets whose real input is hidden
+// are toggled by a plain click.
+func (m *Manager) SetChecked(ctx context.Context, selector string, checked bool) (err error) {
+	const op = "SetChecked"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("selector", selector),
+		attribute.Bool("checked", checked))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return err
+	}
+
+	locator := m.locate(selector)
+
+	step.AddEvent("setting checked state")
+
+	err = m.untilDialog(func() error {
+		return locator.SetChecked(checked, playwright.LocatorSetCheckedOptions{
+			Timeout: playwright.Float(clickTimeout),
+		})
+	})
+	if err != nil {
+		logger.Warn("SetChecked failed, falling back to click", zap.Error(err))
+		step.AddEvent("falling back to click")
+
+		current, stateErr := locator.IsChecked()
+		if stateErr == nil && current != checked {
+			err = m.untilDialog(func() error {
+				return locator.Click(playwright.LocatorClickOptions{
+					Timeout: playwright.Float(clickTimeout),
+				})
+			})
+		}
+
+		if err != nil && stateErr != nil {
+			return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+				apperr.MetaReason:   "set_checked_failed",
+				apperr.MetaStage:    apperr.StageInteraction,
+				apperr.MetaSelector: selector,
+			})
+		}
+	}
+
+	m.settle(step)
+
+	step.AddEvent("verifying state")
+
+	state, err := locator.IsChecked()
+	if err != nil {
+		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "not_checkable",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	if state != checked {
+		return apperr.Wrap(op, apperr.CodeActionFailed, fmt.Errorf("element is still %s", checkedWord(state)), map[string]any{
+			apperr.MetaReason:   "state_not_changed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	return nil
+}
+
+func checkedWord(checked bool) string {
+	if checked {
+		return "checked"
+	}
+
+	return "unchecked"
+}
+
+func (m *Manager) Hover(ctx context.Context, selector string) (err error) {
+	const op = "Hover"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("selector", selector))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return err
+	}
+
+	step.AddEvent("hovering element")
+
+	err = m.locate(selector).Hover(playwright.LocatorHoverOptions{
+		Timeout: playwright.Float(clickTimeout),
+	})
+	if err != nil {
+		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "hover_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	m.settle(step)
+	step.AddEvent("hover completed")
+
+	return nil
+}
+
+// Drag drags the from element onto the to element, or, when to is empty, to
+// the page coordinates x, y (for sliders and free positioning). The mouse
+// moves in steps so sortable lists see intermediate positions.
+func (m *Manager) Drag(ctx context.Context, from, to string, x, y float64) (err error) {
+	const op = "Drag"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, from))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("from", from),
+		attribute.String("to", to))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	from, err = m.resolveTarget(op, from)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(to) != "" {
+		if to, err = m.resolveTarget(op, to); err != nil {
+			return err
+		}
+	}
+
+	source := m.locate(from)
+
+	if err := source.ScrollIntoViewIfNeeded(playwright.LocatorScrollIntoViewIfNeededOptions{
+		Timeout: playwright.Float(waitTimeout),
+	}); err != nil {
+		return apperr.Wrap(op, apperr.CodeNotFound, err, map[string]any{
+			apperr.MetaReason:   "element_not_found",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: from,
+		})
+	}
+
+	start, err := source.BoundingBox()
+	if err != nil || start == nil {
+		return apperr.Wrap(op, apperr.CodeActionFailed, fmt.Errorf("drag source is not visible"), map[string]any{
+			apperr.MetaReason:   "element_not_visible",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: from,
+		})
+	}
+
+	if to != "" {
+		target, err := m.locate(to).BoundingBox(playwright.LocatorBoundingBoxOptions{
+			Timeout: playwright.Float(waitTimeout),
+		})
+		if err != nil || target == nil {
+			return apperr.Wrap(op, apperr.CodeActionFailed, fmt.Errorf("drop target is not visible"), map[string]any{
+				apperr.MetaReason:   "element_not_visible",
+				apperr.MetaStage:    apperr.StageInteraction,
+				apperr.MetaSelector: to,
+			})
+		}
+
+		x = target.X + target.Width/2
+		y = target.Y + target.Height/2
+	}
+
+	step.AddEvent("dragging",
+		attribute.Float64("x", x),
+		attribute.Float64("y", y))
+
+	mouse := m.page.Mouse()
+
+	err = m.untilDialog(func() error {
+		if err := mouse.Move(start.X+start.Width/2, start.Y+start.Height/2); err != nil {
+			return err
+		}
+
+		if err := mouse.Down(); err != nil {
+			return err
+		}
+
+		if err := mouse.Move(x, y, playwright.MouseMoveOptions{Steps: playwright.Int(15)}); err != nil {
+			return err
+		}
+
+		return mouse.Up()
+	})
+	if err != nil {
+		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "drag_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: from,
+		})
+	}
+
+	m.settle(step)
+	step.AddEvent("drag completed")
+
+	return nil
+}
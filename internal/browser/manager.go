@@ -3,6 +3,7 @@ package browser
 import (
 	"ai-agent-task/internal/config"
 	"ai-agent-task/internal/entity"
+	"ai-agent-task/internal/policy"
 	"ai-agent-task/pkg/apperr"
 	"ai-agent-task/pkg/logg"
 	"ai-agent-task/pkg/tracing"
@@ -10,6 +11,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/playwright-community/playwright-go"
@@ -27,32 +30,55 @@ const (
 	retryDelay         = 800 * time.Millisecond
 	clickTimeout       = 15000
 	waitTimeout        = 12000
+	highlightTTL       = 5 * time.Second
 )
 
 type Manager struct {
-	config         *config.Config
-	logger         *zap.Logger
-	tracer         trace.Tracer
-	playwright     *playwright.Playwright
-	browser        playwright.Browser
-	browserContext playwright.BrowserContext
-	page           playwright.Page
-	ready          bool
+	config            *config.Config
+	logger            *zap.Logger
+	tracer            trace.Tracer
+	playwright        *playwright.Playwright
+	browser           playwright.Browser
+	browserContext    playwright.BrowserContext
+	page              playwright.Page
+	urlFilter         *policy.URLFilter
+	tabsMu            sync.Mutex
+	tabs              []*tab
+	newTabs           []int
+	nextTabID         int
+	dialogMu          sync.Mutex
+	pendingDialogs    []*pendingDialog
+	dialogLog         []entity.Dialog
+	dialogOpened      chan struct{}
+	uploadMu          sync.Mutex
+	pendingChooser    playwright.FileChooser
+	uploading         atomic.Bool
+	downloadMu        sync.Mutex
+	downloadDir       string
+	downloads         []entity.Download
+	downloadsInFlight atomic.Int32
+	refsMu            sync.Mutex
+	refs              map[playwright.Page]*elementRegistry
+	ready             bool
 }
 
 type Params struct {
 	fx.In
 
-	Config *config.Config
-	Logger *zap.Logger
+	Config    *config.Config
+	Logger    *zap.Logger
+	URLFilter *policy.URLFilter
 }
 
 func NewManager(params Params) *Manager {
 	return &Manager{
-		config: params.Config,
-		logger: params.Logger.With(zap.String(logg.Layer, browserManagerName)),
-		tracer: otel.Tracer(browserTracer),
-		ready:  false,
+		config:       params.Config,
+		logger:       params.Logger.With(zap.String(logg.Layer, browserManagerName)),
+		tracer:       otel.Tracer(browserTracer),
+		urlFilter:    params.URLFilter,
+		dialogOpened: make(chan struct{}),
+		refs:         make(map[playwright.Page]*elementRegistry),
+		ready:        false,
 	}
 }
 
@@ -144,6 +170,15 @@ func (m *Manager) launchPersistent(ctx context.Context) (err error) {
 
 	m.browserContext = browserContext
 
+	if err := m.installRequestFilter(); err != nil {
+		return apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "route_install_failed",
+			apperr.MetaStage:  apperr.StageBrowser,
+		})
+	}
+
+	m.trackContext()
+
 	pages := browserContext.Pages()
 
 	if len(pages) > 0 {
@@ -157,6 +192,7 @@ func (m *Manager) launchPersistent(ctx context.Context) (err error) {
 				apperr.MetaStage:  apperr.StageBrowser,
 			})
 		}
+		m.trackPage(page, false)
 		m.page = page
 		logger.Info("Created new page")
 	}
@@ -217,6 +253,15 @@ func (m *Manager) launchNew(ctx context.Context) (err error) {
 
 	m.browserContext = browserContext
 
+	if err := m.installRequestFilter(); err != nil {
+		return apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "route_install_failed",
+			apperr.MetaStage:  apperr.StageBrowser,
+		})
+	}
+
+	m.trackContext()
+
 	page, err := browserContext.NewPage()
 	if err != nil {
 		return apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
@@ -224,6 +269,7 @@ func (m *Manager) launchNew(ctx context.Context) (err error) {
 			apperr.MetaStage:  apperr.StageBrowser,
 		})
 	}
+	m.trackPage(page, false)
 	m.page = page
 
 	m.ready = true
@@ -232,6 +278,33 @@ func (m *Manager) launchNew(ctx context.Context) (err error) {
 	return nil
 }
 
+func (m *Manager) installRequestFilter() error {
+	if m.urlFilter == nil || !m.urlFilter.BlockRequests() {
+		return nil
+	}
+
+	return m.browserContext.Route("**/*", func(route playwright.Route) {
+		request := route.Request()
+		isNavigation := request.IsNavigationRequest() && request.Frame() != nil && request.Frame().ParentFrame() == nil
+
+		if allowed, reason := m.urlFilter.CheckRequest(request.URL(), isNavigation); !allowed {
+			m.logger.Warn("Request blocked",
+				zap.String(logg.URL, request.URL()),
+				zap.String("reason", reason))
+
+			if err := route.Abort("blockedbyclient"); err != nil {
+				m.logger.Debug("Failed to abort request", zap.Error(err))
+			}
+
+			return
+		}
+
+		if err := route.Continue(); err != nil {
+			m.logger.Debug("Failed to continue request", zap.Error(err))
+		}
+	})
+}
+
 func (m *Manager) Close(ctx context.Context) (err error) {
 	const op = "Close"
 	logger := m.logger.With(zap.String(logg.Operation, op))
@@ -290,6 +363,13 @@ func (m *Manager) ensurePageActive(ctx context.Context) error {
 
 	m.logger.Info("Page closed, reconnecting to active page...")
 
+	if t := m.lastOpenTab(); t != nil {
+		m.page = t.page
+		m.logger.Info("Switched to most recent open tab", zap.Int("tab_id", t.id))
+
+		return nil
+	}
+
 	pages := m.browserContext.Pages()
 
 	if len(pages) > 0 {
@@ -310,6 +390,7 @@ func (m *Manager) ensurePageActive(ctx context.Context) error {
 		return fmt.Errorf("failed to create new page: %w", err)
 	}
 
+	m.trackPage(page, false)
 	m.page = page
 	m.logger.Info("Created new page")
 
@@ -350,12 +431,134 @@ func (m *Manager) Navigate(ctx context.Context, url string) (err error) {
 		})
 	}
 
-	time.Sleep(500 * time.Millisecond)
+	m.settle(step)
+	step.AddEvent("navigation completed")
+
+	return nil
+}
+
+func (m *Manager) GoBack(ctx context.Context) (err error) {
+	const op = "GoBack"
+	logger := m.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op)
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	step.AddEvent("going back")
+
+	response, err := m.page.GoBack(playwright.PageGoBackOptions{
+		Timeout:   playwright.Float(float64(m.config.BrowserConfig.Timeout)),
+		WaitUntil: playwright.WaitUntilStateDomcontentloaded,
+	})
+	if err != nil {
+		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "go_back_failed",
+			apperr.MetaStage:  apperr.StageNavigation,
+		})
+	}
+
+	if response == nil {
+		step.AddEvent("no history entry")
+	}
+
+	m.settle(step)
+	step.AddEvent("navigation completed")
+
+	return nil
+}
+
+func (m *Manager) GoForward(ctx context.Context) (err error) {
+	const op = "GoForward"
+	logger := m.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op)
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	step.AddEvent("going forward")
+
+	response, err := m.page.GoForward(playwright.PageGoForwardOptions{
+		Timeout:   playwright.Float(float64(m.config.BrowserConfig.Timeout)),
+		WaitUntil: playwright.WaitUntilStateDomcontentloaded,
+	})
+	if err != nil {
+		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "go_forward_failed",
+			apperr.MetaStage:  apperr.StageNavigation,
+		})
+	}
+
+	if response == nil {
+		step.AddEvent("no history entry")
+	}
+
+	m.settle(step)
 	step.AddEvent("navigation completed")
 
 	return nil
 }
 
+func (m *Manager) Reload(ctx context.Context) (err error) {
+	const op = "Reload"
+	logger := m.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op)
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	step.AddEvent("reloading page")
+
+	_, err = m.page.Reload(playwright.PageReloadOptions{
+		Timeout:   playwright.Float(float64(m.config.BrowserConfig.Timeout)),
+		WaitUntil: playwright.WaitUntilStateDomcontentloaded,
+	})
+	if err != nil {
+		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "reload_failed",
+			apperr.MetaStage:  apperr.StageNavigation,
+		})
+	}
+
+	m.settle(step)
+	step.AddEvent("reload completed")
+
+	return nil
+}
+
 func (m *Manager) Click(ctx context.Context, selector string) (err error) {
 	const op = "Click"
 	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
@@ -375,7 +578,13 @@ func (m *Manager) Click(ctx context.Context, selector string) (err error) {
 		})
 	}
 
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return err
+	}
+
 	var lastErr error
+	locator := m.locate(selector)
 	strategies := []struct {
 		name string
 		fn   func() error
@@ -383,46 +592,25 @@ func (m *Manager) Click(ctx context.Context, selector string) (err error) {
 		{
 			name: "wait_and_click",
 			fn: func() error {
-				result, err := m.page.Evaluate(fmt.Sprintf(`
-					(() => {
-						const el = document.querySelector('%s');
-						if (!el) return {success: false, error: 'element not found'};
-						
-						const rect = el.getBoundingClientRect();
-						const style = window.getComputedStyle(el);
-						
-						const isVisible = (
-							rect.width > 0 && 
-							rect.height > 0 && 
-							style.display !== 'none' && 
-							style.visibility !== 'hidden' &&
-							parseFloat(style.opacity) > 0
-						);
-						
-						if (!isVisible) return {success: false, error: 'element not visible'};
-						
-						el.scrollIntoView({behavior: 'instant', block: 'center'});
-						
-						return {success: true};
-					})()
-				`, escapeSelector(selector)))
-				
+				if err := locator.ScrollIntoViewIfNeeded(playwright.LocatorScrollIntoViewIfNeededOptions{
+					Timeout: playwright.Float(waitTimeout),
+				}); err != nil {
+					return fmt.Errorf("element check failed: %w", err)
+				}
+
+				visible, err := locator.IsVisible()
 				if err != nil {
 					return fmt.Errorf("visibility check failed: %w", err)
 				}
 
-				if resultMap, ok := result.(map[string]interface{}); ok {
-					if success, ok := resultMap["success"].(bool); ok && !success {
-						if errMsg, ok := resultMap["error"].(string); ok {
-							return fmt.Errorf("element check failed: %s", errMsg)
-						}
-					}
+				if !visible {
+					return fmt.Errorf("element check failed: element not visible")
 				}
 
-				time.Sleep(300 * time.Millisecond)
-
-				err = m.page.Click(selector, playwright.PageClickOptions{
-					Timeout: playwright.Float(clickTimeout),
+				err = m.untilDialog(func() error {
+					return locator.Click(playwright.LocatorClickOptions{
+						Timeout: playwright.Float(clickTimeout),
+					})
 				})
 				if err != nil {
 					return fmt.Errorf("click failed: %w", err)
@@ -434,22 +622,17 @@ func (m *Manager) Click(ctx context.Context, selector string) (err error) {
 		{
 			name: "force_click",
 			fn: func() error {
-				_, err := m.page.Evaluate(fmt.Sprintf(`
-					(() => {
-						const el = document.querySelector('%s');
-						if (el) {
-							el.scrollIntoView({behavior: 'instant', block: 'center'});
-						}
-					})()
-				`, escapeSelector(selector)))
-				
-				if err == nil {
+				if err := locator.ScrollIntoViewIfNeeded(playwright.LocatorScrollIntoViewIfNeededOptions{
+					Timeout: playwright.Float(5000),
+				}); err == nil {
 					time.Sleep(300 * time.Millisecond)
 				}
 
-				err = m.page.Click(selector, playwright.PageClickOptions{
-					Timeout: playwright.Float(clickTimeout),
-					Force:   playwright.Bool(true),
+				err := m.untilDialog(func() error {
+					return locator.Click(playwright.LocatorClickOptions{
+						Timeout: playwright.Float(clickTimeout),
+						Force:   playwright.Bool(true),
+					})
 				})
 				if err != nil {
 					return fmt.Errorf("force click failed: %w", err)
@@ -461,87 +644,48 @@ func (m *Manager) Click(ctx context.Context, selector string) (err error) {
 		{
 			name: "js_direct_click",
 			fn: func() error {
-				result, err := m.page.Evaluate(fmt.Sprintf(`
-					(() => {
-						const el = document.querySelector('%s');
-						if (!el) return {success: false, error: 'element not found'};
-						
+				err := m.untilDialog(func() error {
+					_, err := locator.Evaluate(`el => {
 						el.scrollIntoView({behavior: 'instant', block: 'center'});
-						
-						return new Promise((resolve) => {
-							setTimeout(() => {
-								try {
-									el.click();
-									resolve({success: true});
-								} catch(e) {
-									resolve({success: false, error: e.message});
-								}
-							}, 200);
-						});
-					})()
-				`, escapeSelector(selector)))
-				
-				if err != nil {
-					return fmt.Errorf("js evaluation failed: %w", err)
-				}
+						el.click();
+					}`, nil, playwright.LocatorEvaluateOptions{
+						Timeout: playwright.Float(5000),
+					})
 
-				if resultMap, ok := result.(map[string]interface{}); ok {
-					if success, ok := resultMap["success"].(bool); ok && !success {
-						if errMsg, ok := resultMap["error"].(string); ok {
-							return fmt.Errorf("js click failed: %s", errMsg)
-						}
-					}
+					return err
+				})
+				if err != nil {
+					return fmt.Errorf("js click failed: %w", err)
 				}
 
-				time.Sleep(300 * time.Millisecond)
-
 				return nil
 			},
 		},
 		{
 			name: "mouse_click",
 			fn: func() error {
-				result, err := m.page.Evaluate(fmt.Sprintf(`
-					(() => {
-						const el = document.querySelector('%s');
-						if (!el) return {success: false, error: 'element not found'};
-						
-						el.scrollIntoView({behavior: 'instant', block: 'center'});
-						
-						const rect = el.getBoundingClientRect();
-						return {
-							success: true,
-							x: rect.left + rect.width / 2,
-							y: rect.top + rect.height / 2
-						};
-					})()
-				`, escapeSelector(selector)))
-				
+				_ = locator.ScrollIntoViewIfNeeded(playwright.LocatorScrollIntoViewIfNeededOptions{
+					Timeout: playwright.Float(5000),
+				})
+
+				// The bounding box is relative to the main frame, so this
+				// also works for elements inside iframes.
+				box, err := locator.BoundingBox(playwright.LocatorBoundingBoxOptions{
+					Timeout: playwright.Float(5000),
+				})
 				if err != nil {
 					return fmt.Errorf("coordinate calculation failed: %w", err)
 				}
 
-				resultMap, ok := result.(map[string]interface{})
-				if !ok {
-					return fmt.Errorf("invalid result format")
-				}
-
-				if success, ok := resultMap["success"].(bool); !ok || !success {
-					if errMsg, ok := resultMap["error"].(string); ok {
-						return fmt.Errorf("element check failed: %s", errMsg)
-					}
-					return fmt.Errorf("element check failed")
-				}
-
-				x, okX := resultMap["x"].(float64)
-				y, okY := resultMap["y"].(float64)
-				if !okX || !okY {
-					return fmt.Errorf("invalid coordinates")
+				if box == nil {
+					return fmt.Errorf("element check failed: element not visible")
 				}
 
 				time.Sleep(300 * time.Millisecond)
 
-				err = m.page.Mouse().Click(x, y)
+				err = m.untilDialog(func() error {
+					return m.page.Mouse().Click(box.X+box.Width/2, box.Y+box.Height/2)
+				})
 				if err != nil {
 					return fmt.Errorf("mouse click failed: %w", err)
 				}
@@ -567,7 +711,7 @@ func (m *Manager) Click(ctx context.Context, selector string) (err error) {
 
 		err = strategy.fn()
 		if err == nil {
-			time.Sleep(300 * time.Millisecond)
+			m.settle(step)
 			step.AddEvent("click completed")
 
 			return nil
@@ -584,10 +728,6 @@ func (m *Manager) Click(ctx context.Context, selector string) (err error) {
 	})
 }
 
-func escapeSelector(selector string) string {
-	return strings.ReplaceAll(selector, "'", "\\'")
-}
-
 func (m *Manager) ClickAtCoordinates(ctx context.Context, x, y float64) (err error) {
 	const op = "ClickAtCoordinates"
 	logger := m.logger.With(zap.String(logg.Operation, op))
@@ -611,7 +751,9 @@ func (m *Manager) ClickAtCoordinates(ctx context.Context, x, y float64) (err err
 
 	step.AddEvent("clicking at coordinates")
 
-	err = m.page.Mouse().Click(x, y)
+	err = m.untilDialog(func() error {
+		return m.page.Mouse().Click(x, y)
+	})
 	if err != nil {
 		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
 			apperr.MetaReason: "click_coordinates_failed",
@@ -619,7 +761,7 @@ func (m *Manager) ClickAtCoordinates(ctx context.Context, x, y float64) (err err
 		})
 	}
 
-	time.Sleep(300 * time.Millisecond)
+	m.settle(step)
 	step.AddEvent("click completed")
 
 	return nil
@@ -644,7 +786,13 @@ func (m *Manager) Fill(ctx context.Context, selector, value string) (err error)
 		})
 	}
 
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return err
+	}
+
 	var lastErr error
+	locator := m.locate(selector)
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			logger.Info("Retrying fill", zap.Int("attempt", attempt))
@@ -653,7 +801,7 @@ func (m *Manager) Fill(ctx context.Context, selector, value string) (err error)
 
 		step.AddEvent(fmt.Sprintf("waiting for element (attempt %d)", attempt+1))
 
-		_, err = m.page.WaitForSelector(selector, playwright.PageWaitForSelectorOptions{
+		err = locator.WaitFor(playwright.LocatorWaitForOptions{
 			Timeout: playwright.Float(5000),
 			State:   playwright.WaitForSelectorStateVisible,
 		})
@@ -666,19 +814,21 @@ func (m *Manager) Fill(ctx context.Context, selector, value string) (err error)
 		step.AddEvent(fmt.Sprintf("filling field (attempt %d)", attempt+1))
 
 		if attempt > 0 {
-			m.page.Fill(selector, "", playwright.PageFillOptions{
+			locator.Fill("", playwright.LocatorFillOptions{
 				Timeout: playwright.Float(5000),
 			})
 			time.Sleep(200 * time.Millisecond)
 		}
 
-		err = m.page.Fill(selector, value, playwright.PageFillOptions{
-			Timeout: playwright.Float(5000),
-			Force:   playwright.Bool(attempt > 0),
+		err = m.untilDialog(func() error {
+			return locator.Fill(value, playwright.LocatorFillOptions{
+				Timeout: playwright.Float(5000),
+				Force:   playwright.Bool(attempt > 0),
+			})
 		})
 
 		if err == nil {
-			time.Sleep(300 * time.Millisecond)
+			m.settle(step)
 			step.AddEvent("fill completed")
 
 			return nil
@@ -714,9 +864,24 @@ func (m *Manager) Press(ctx context.Context, key string) (err error) {
 		})
 	}
 
-	step.AddEvent("pressing key")
+	keys := strings.Fields(key)
+	if len(keys) == 0 {
+		return apperr.InvalidReqError(op, "key", fmt.Errorf("key cannot be empty"))
+	}
+
+	step.AddEvent("pressing key", attribute.Int("keys", len(keys)))
+
+	// A sequence like "Control+A Backspace" is pressed key by key; each
+	// part may be a chord.
+	err = m.untilDialog(func() error {
+		for _, k := range keys {
+			if err := m.page.Keyboard().Press(normalizeKey(k)); err != nil {
+				return err
+			}
+		}
 
-	err = m.page.Keyboard().Press(key)
+		return nil
+	})
 	if err != nil {
 		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
 			apperr.MetaReason: "press_failed",
@@ -724,22 +889,21 @@ func (m *Manager) Press(ctx context.Context, key string) (err error) {
 		})
 	}
 
-	if key == "Enter" {
-		time.Sleep(1 * time.Second)
-	} else {
-		time.Sleep(300 * time.Millisecond)
-	}
-
+	m.settle(step)
 	step.AddEvent("press completed")
 
 	return nil
 }
 
-func (m *Manager) Scroll(ctx context.Context, direction string, amount int) (err error) {
+// Scroll scrolls the container of selector (the element itself or its
+// nearest scrollable ancestor) or, with an empty selector, the dominant
+// scrollable container under the viewport center.
+func (m *Manager) Scroll(ctx context.Context, selector, direction string, amount int) (scrolled *entity.ScrollResult, err error) {
 	const op = "Scroll"
-	logger := m.logger.With(zap.String(logg.Operation, op))
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
 
 	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("selector", selector),
 		attribute.String("direction", direction),
 		attribute.Int("amount", amount))
 	defer func() {
@@ -747,41 +911,45 @@ func (m *Manager) Scroll(ctx context.Context, direction string, amount int) (err
 	}()
 
 	if !m.ready {
-		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+		return nil, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
 	}
 
 	if err := m.ensurePageActive(ctx); err != nil {
-		return apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+		return nil, apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
 			apperr.MetaReason: "page_not_active",
 		})
 	}
 
-	var script string
-
-	if direction == "down" {
-		script = fmt.Sprintf("window.scrollBy(0, %d)", amount)
-	} else if direction == "up" {
-		script = fmt.Sprintf("window.scrollBy(0, -%d)", amount)
-	} else if direction == "bottom" {
-		script = "window.scrollTo(0, document.body.scrollHeight)"
-	} else if direction == "top" {
-		script = "window.scrollTo(0, 0)"
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return nil, err
 	}
 
-	step.AddEvent("scrolling page")
+	step.AddEvent("scrolling container")
 
-	_, err = m.page.Evaluate(script)
+	resultMap, err := m.evaluateScroll(selector, scrollScript(), map[string]interface{}{
+		"direction": direction,
+		"amount":    amount,
+	})
 	if err != nil {
-		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
-			apperr.MetaReason: "scroll_failed",
-			apperr.MetaStage:  apperr.StageInteraction,
+		return nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "scroll_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
 		})
 	}
 
-	time.Sleep(500 * time.Millisecond)
-	step.AddEvent("scroll completed")
+	scrolled = parseScrollResult(resultMap)
 
-	return nil
+	if scrolled.Moved {
+		m.settle(step)
+	}
+
+	step.AddEvent("scroll completed",
+		attribute.String("container", scrolled.Container),
+		attribute.Bool("moved", scrolled.Moved))
+
+	return scrolled, nil
 }
 
 func (m *Manager) WaitForSelector(ctx context.Context, selector string, timeout int) (err error) {
@@ -803,7 +971,12 @@ func (m *Manager) WaitForSelector(ctx context.Context, selector string, timeout
 		})
 	}
 
-	_, err = m.page.WaitForSelector(selector, playwright.PageWaitForSelectorOptions{
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return err
+	}
+
+	err = m.locate(selector).WaitFor(playwright.LocatorWaitForOptions{
 		Timeout: playwright.Float(float64(timeout)),
 	})
 
@@ -836,7 +1009,14 @@ func (m *Manager) GetElementText(ctx context.Context, selector string) (text str
 		})
 	}
 
-	element, err := m.page.QuerySelector(selector)
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return "", err
+	}
+
+	locator := m.locate(selector)
+
+	count, err := locator.Count()
 	if err != nil {
 		return "", apperr.Wrap(op, apperr.CodeNotFound, err, map[string]any{
 			apperr.MetaReason:   "element_not_found",
@@ -844,11 +1024,13 @@ func (m *Manager) GetElementText(ctx context.Context, selector string) (text str
 		})
 	}
 
-	if element == nil {
+	if count == 0 {
 		return "", apperr.NotFoundError(op, fmt.Errorf("element not found: %s", selector))
 	}
 
-	text, err = element.TextContent()
+	text, err = locator.TextContent(playwright.LocatorTextContentOptions{
+		Timeout: playwright.Float(5000),
+	})
 	if err != nil {
 		return "", apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
 			apperr.MetaReason: "text_content_failed",
@@ -877,12 +1059,39 @@ func (m *Manager) Screenshot(ctx context.Context, path string) (err error) {
 		})
 	}
 
-	_, err = m.page.Screenshot(playwright.PageScreenshotOptions{
+	if m.pendingDialogOnActivePage() {
+		return apperr.WrapErrorWithReason(op, apperr.CodeActionFailed, "dialog_open")
+	}
+
+	options := playwright.PageScreenshotOptions{
 		Path:     playwright.String(path),
 		FullPage: playwright.Bool(false),
 		Type:     playwright.ScreenshotTypeJpeg,
 		Quality:  playwright.Int(60),
-	})
+	}
+
+	if m.config.RedactionConfig.BlurScreenshots {
+		step.AddEvent("blurring sensitive inputs")
+		options.Style = playwright.String(sensitiveInputsStyle)
+	}
+
+	if m.config.BrowserConfig.ScreenshotMarks {
+		if marks := m.screenshotMarks(); len(marks) > 0 {
+			step.AddEvent("drawing element marks")
+
+			if _, err := m.page.Evaluate(marksScript(), map[string]interface{}{"marks": marks}); err != nil {
+				logger.Warn("Failed to draw element marks", zap.Error(err))
+			}
+
+			defer func() {
+				if _, err := m.page.Evaluate(removeMarksScript()); err != nil {
+					logger.Warn("Failed to remove element marks", zap.Error(err))
+				}
+			}()
+		}
+	}
+
+	_, err = m.page.Screenshot(options)
 
 	if err != nil {
 		return apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
@@ -894,6 +1103,19 @@ func (m *Manager) Screenshot(ctx context.Context, path string) (err error) {
 	return nil
 }
 
+const sensitiveInputsStyle = `
+input[type="password"],
+input[autocomplete^="cc-"],
+input[autocomplete="one-time-code"],
+input[name*="card" i], input[id*="card" i],
+input[name*="cvv" i], input[id*="cvv" i],
+input[name*="cvc" i], input[id*="cvc" i],
+iframe[src*="pay" i], iframe[name*="card" i], iframe[title*="card" i],
+[data-sensitive] {
+	filter: blur(12px) !important;
+}
+`
+
 func (m *Manager) GetPageState(ctx context.Context) (state *entity.PageState, err error) {
 	const op = "GetPageState"
 	logger := m.logger.With(zap.String(logg.Operation, op))
@@ -914,8 +1136,39 @@ func (m *Manager) GetPageState(ctx context.Context) (state *entity.PageState, er
 	}
 
 	url := m.page.URL()
+	dialogs := m.currentDialogs()
+
+	// An open dialog blocks scripts on the page, so report it alone.
+	if m.pendingDialogOnActivePage() {
+		step.AddEvent("dialog open, skipping elements")
+
+		return &entity.PageState{
+			URL:       url,
+			Dialogs:   dialogs,
+			Timestamp: time.Now(),
+		}, nil
+	}
+
 	title, _ := m.page.Title()
 
+	if m.config.BrowserConfig.PageState == PageStateAccessibility {
+		step.AddEvent("reading accessibility tree")
+
+		nodes, err := m.accessibilityNodes(ctx)
+		if err == nil {
+			return &entity.PageState{
+				URL:             url,
+				Title:           title,
+				AXNodes:         nodes,
+				Dialogs:         dialogs,
+				FileChooserOpen: m.fileChooserOpen(),
+				Timestamp:       time.Now(),
+			}, nil
+		}
+
+		logger.Warn("Failed to read accessibility tree, falling back to DOM", zap.Error(err))
+	}
+
 	elements, err := m.GetElements(ctx)
 	if err != nil {
 		logger.Warn("Failed to get elements", zap.Error(err))
@@ -923,10 +1176,12 @@ func (m *Manager) GetPageState(ctx context.Context) (state *entity.PageState, er
 	}
 
 	return &entity.PageState{
-		URL:       url,
-		Title:     title,
-		Elements:  elements,
-		Timestamp: time.Now(),
+		URL:             url,
+		Title:           title,
+		Elements:        elements,
+		Dialogs:         dialogs,
+		FileChooserOpen: m.fileChooserOpen(),
+		Timestamp:       time.Now(),
 	}, nil
 }
 
@@ -963,7 +1218,12 @@ func (m *Manager) GetElements(ctx context.Context) (elements []entity.Element, e
 		})
 	}
 
-	elementsList, ok := result.([]interface{})
+	resultMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, apperr.WrapErrorWithReason(op, apperr.CodeInternal, "unexpected_result_type")
+	}
+
+	elementsList, ok := resultMap["elements"].([]interface{})
 	if !ok {
 		return nil, apperr.WrapErrorWithReason(op, apperr.CodeInternal, "unexpected_result_type")
 	}
@@ -976,33 +1236,171 @@ func (m *Manager) GetElements(ctx context.Context) (elements []entity.Element, e
 			continue
 		}
 
-		elem := entity.Element{
-			Tag:        getString(elemMap, "tag"),
-			Text:       strings.TrimSpace(getString(elemMap, "text")),
-			Selector:   getString(elemMap, "selector"),
-			Visible:    getBool(elemMap, "visible"),
-			Clickable:  getBool(elemMap, "clickable"),
-			Attributes: make(map[string]string),
-			BoundingBox: entity.BoundingBox{
-				X:      getFloat(elemMap, "x"),
-				Y:      getFloat(elemMap, "y"),
-				Width:  getFloat(elemMap, "width"),
-				Height: getFloat(elemMap, "height"),
-			},
+		elements = append(elements, parseElement(elemMap))
+	}
+
+	m.registerElements(getString(resultMap, "doc"), elements)
+
+	return elements, nil
+}
+
+func (m *Manager) DescribeElement(ctx context.Context, selector string) (elem *entity.Element, err error) {
+	const op = "DescribeElement"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op, attribute.String("selector", selector))
+	defer func() {
+		step.End(err)
+	}()
+
+	return m.describeElement(ctx, op, map[string]interface{}{"selector": selector})
+}
+
+func (m *Manager) ElementAt(ctx context.Context, x, y float64) (elem *entity.Element, err error) {
+	const op = "ElementAt"
+	logger := m.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.Float64("x", x),
+		attribute.Float64("y", y))
+	defer func() {
+		step.End(err)
+	}()
+
+	return m.describeElement(ctx, op, map[string]interface{}{"x": x, "y": y})
+}
+
+func (m *Manager) describeElement(ctx context.Context, op string, arg map[string]interface{}) (*entity.Element, error) {
+	if !m.ready {
+		return nil, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	selector, bySelector := arg["selector"].(string)
+
+	var result interface{}
+	var err error
+
+	target := selector
+
+	if bySelector {
+		if target, err = m.resolveTarget(op, selector); err != nil {
+			return nil, err
 		}
 
-		if attrs, ok := elemMap["attributes"].(map[string]interface{}); ok {
-			for k, v := range attrs {
-				if str, ok := v.(string); ok {
-					elem.Attributes[k] = str
+		locator := m.locate(target)
+
+		if count, err := locator.Count(); err != nil || count == 0 {
+			return nil, apperr.NotFoundError(op, fmt.Errorf("element not found"))
+		}
+
+		result, err = locator.Evaluate(describeElementScript(), nil, playwright.LocatorEvaluateOptions{
+			Timeout: playwright.Float(3000),
+		})
+	} else {
+		result, err = m.page.Evaluate(elementAtScript(), arg)
+	}
+
+	if err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "evaluate_failed",
+		})
+	}
+
+	elemMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, apperr.NotFoundError(op, fmt.Errorf("element not found"))
+	}
+
+	elem := parseElement(elemMap)
+
+	if bySelector {
+		elem.Selector = selector
+
+		// Coordinates from inside an iframe are relative to that frame.
+		if isFrameSelector(target) {
+			if box, err := m.locate(target).BoundingBox(); err == nil && box != nil {
+				elem.BoundingBox = entity.BoundingBox{
+					X:      box.X + box.Width/2,
+					Y:      box.Y + box.Height/2,
+					Width:  box.Width,
+					Height: box.Height,
 				}
 			}
 		}
+	}
+
+	return &elem, nil
+}
 
-		elements = append(elements, elem)
+func parseElement(elemMap map[string]interface{}) entity.Element {
+	elem := entity.Element{
+		ID:         int(getFloat(elemMap, "id")),
+		Tag:        getString(elemMap, "tag"),
+		Text:       strings.TrimSpace(getString(elemMap, "text")),
+		Selector:   getString(elemMap, "selector"),
+		Visible:    getBool(elemMap, "visible"),
+		Clickable:  getBool(elemMap, "clickable"),
+		Attributes: make(map[string]string),
+		BoundingBox: entity.BoundingBox{
+			X:      getFloat(elemMap, "x"),
+			Y:      getFloat(elemMap, "y"),
+			Width:  getFloat(elemMap, "width"),
+			Height: getFloat(elemMap, "height"),
+		},
 	}
 
-	return elements, nil
+	if attrs, ok := elemMap["attributes"].(map[string]interface{}); ok {
+		for k, v := range attrs {
+			if str, ok := v.(string); ok {
+				elem.Attributes[k] = str
+			}
+		}
+	}
+
+	return elem
+}
+
+func (m *Manager) Highlight(ctx context.Context, box entity.BoundingBox, label string) (err error) {
+	const op = "Highlight"
+	logger := m.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("label", label))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	_, err = m.page.Evaluate(highlightScript(), map[string]interface{}{
+		"x":      box.X,
+		"y":      box.Y,
+		"width":  box.Width,
+		"height": box.Height,
+		"label":  label,
+		"ttl":    highlightTTL.Milliseconds(),
+	})
+	if err != nil {
+		return apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "evaluate_failed",
+		})
+	}
+
+	return nil
 }
 
 func (m *Manager) EvaluateJS(ctx context.Context, script string) (result interface{}, err error) {
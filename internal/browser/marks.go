@@ -0,0 +1,49 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+)
+
+// maxMarkedElements matches the number of clickable elements the agent lists
+// for the model, so every mark has a line in the element list.
+const maxMarkedElements = 40
+
+func (m *Manager) screenshotMarks() []map[string]interface{} {
+	m.refsMu.Lock()
+	registry := m.refs[m.page]
+	m.refsMu.Unlock()
+
+	if registry == nil {
+		return nil
+	}
+
+	marks := make([]map[string]interface{}, 0, len(registry.clickable))
+
+	for _, elem := range registry.clickable {
+		marks = append(marks, map[string]interface{}{
+			"id":     elem.ID,
+			"x":      elem.BoundingBox.X,
+			"y":      elem.BoundingBox.Y,
+			"width":  elem.BoundingBox.Width,
+			"height": elem.BoundingBox.Height,
+		})
+	}
+
+	return marks
+}
+
+func clickableElements(elements []entity.Element) []entity.Element {
+	clickable := []entity.Element{}
+
+	for _, elem := range elements {
+		if len(clickable) >= maxMarkedElements {
+			break
+		}
+
+		if elem.Clickable && elem.ID > 0 {
+			clickable = append(clickable, elem)
+		}
+	}
+
+	return clickable
+}
@@ -0,0 +1,301 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// ReadPage returns the readable content of the page as Markdown, split into
+// pages of about BrowserConfig.ReadChunkChars. With a selector only that
+// element is read, otherwise the main content is detected and navigation,
+// footers and sidebars are left out.
+func (m *Manager) ReadPage(ctx context.Context, selector string, page int) (content *entity.PageContent, err error) {
+	const op = "ReadPage"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("selector", selector),
+		attribute.Int("page", page))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return nil, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	target := "body"
+	if selector != "" {
+		target = selector
+	}
+
+	step.AddEvent("extracting content")
+
+	result, err := m.locate(target).Evaluate(readPageScript(), map[string]interface{}{
+		"auto": selector == "",
+	}, playwright.LocatorEvaluateOptions{
+		Timeout: playwright.Float(waitTimeout),
+	})
+	if err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeNotFound, err, map[string]any{
+			apperr.MetaReason:   "element_not_found",
+			apperr.MetaStage:    apperr.StagePageState,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	resultMap, _ := result.(map[string]interface{})
+	chunks := splitMarkdown(getString(resultMap, "markdown"), m.config.BrowserConfig.ReadChunkChars)
+
+	if page < 1 {
+		page = 1
+	}
+
+	if page > len(chunks) {
+		return nil, apperr.InvalidReqError(op, "page", fmt.Errorf("page %d does not exist, the content has %d pages", page, len(chunks)))
+	}
+
+	step.AddEvent("content extracted", attribute.Int("pages", len(chunks)))
+
+	return &entity.PageContent{
+		URL:      m.page.URL(),
+		Title:    getString(resultMap, "title"),
+		Scope:    getString(resultMap, "scope"),
+		Markdown: chunks[page-1],
+		Page:     page,
+		Pages:    len(chunks),
+	}, nil
+}
+
+// splitMarkdown cuts text into chunks of at most limit characters, breaking
+// between paragraphs where possible. It always returns at least one chunk.
+func splitMarkdown(text string, limit int) []string {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+
+	for _, block := range strings.Split(text, "\n\n") {
+		size := utf8.RuneCountInString(block)
+
+		if utf8.RuneCountInString(current.String())+size+2 > limit {
+			flush()
+		}
+
+		for size > limit {
+			runes := []rune(block)
+			chunks = append(chunks, string(runes[:limit]))
+			block = string(runes[limit:])
+			size -= limit
+		}
+
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+
+		current.WriteString(block)
+	}
+
+	flush()
+
+	if len(chunks) == 0 {
+		return []string{""}
+	}
+
+	return chunks
+}
+
+// readPageScript converts an element to Markdown. In auto mode the element
+// is the body and the script first looks for the main content the way
+// readability tools do: explicit article/main landmarks, then the block
+// with the most paragraph text and the lowest link density.
+func readPageScript() string {
+	return `(el, arg) => {
+		const doc = el.ownerDocument;
+		const skipTags = new Set(['script', 'style', 'noscript', 'template', 'svg', 'canvas', 'iframe', 'frame', 'object', 'embed', 'input', 'select', 'textarea', 'button']);
+		const boilerplateTags = new Set(['nav', 'footer', 'aside', 'header', 'form']);
+		const boilerplateRe = /(^|[\s_-])(nav|menu|footer|sidebar|cookie|banner|promo|advert|ads?|share|social|related|comments?|breadcrumbs?|modal|popup)([\s_-]|$)/i;
+
+		const textLength = (node) => (node.innerText || node.textContent || '').replace(/\s+/g, ' ').trim().length;
+
+		const linkDensity = (node) => {
+			const total = textLength(node);
+			if (!total) return 0;
+			let links = 0;
+			node.querySelectorAll('a').forEach(a => { links += textLength(a); });
+			return links / total;
+		};
+
+		const findMain = () => {
+			const landmarks = Array.from(doc.querySelectorAll('article, main, [role="main"]'))
+				.filter(n => textLength(n) > 200)
+				.sort((a, b) => textLength(b) - textLength(a));
+			if (landmarks.length) return landmarks[0];
+
+			const scores = new Map();
+			doc.querySelectorAll('p, pre, td, blockquote, li').forEach(p => {
+				const text = (p.innerText || '').trim();
+				if (text.length < 25) return;
+				const score = 1 + text.split(/[,.]/).length + Math.min(Math.floor(text.length / 100), 3);
+				const parent = p.parentElement;
+				const grand = parent && parent.parentElement;
+				if (parent) scores.set(parent, (scores.get(parent) || 0) + score);
+				if (grand) scores.set(grand, (scores.get(grand) || 0) + score / 2);
+			});
+
+			let best = null;
+			let bestScore = 0;
+			scores.forEach((score, node) => {
+				const adjusted = score * (1 - linkDensity(node));
+				if (adjusted > bestScore) {
+					best = node;
+					bestScore = adjusted;
+				}
+			});
+
+			return best && bestScore > 10 ? best : null;
+		};
+
+		let root = el;
+		let scope = 'selector';
+		if (arg.auto) {
+			root = findMain() || doc.body;
+			scope = root === doc.body ? 'body' : 'main content';
+		}
+
+		const hidden = (node) => {
+			if (node.hidden || node.getAttribute('aria-hidden') === 'true') return true;
+			const style = doc.defaultView.getComputedStyle(node);
+			return style.display === 'none' || style.visibility === 'hidden';
+		};
+
+		const isBoilerplate = (node) => {
+			if (!arg.auto || node === root) return false;
+			const tag = node.tagName.toLowerCase();
+			if (boilerplateTags.has(tag)) return true;
+			const role = node.getAttribute('role');
+			if (role === 'navigation' || role === 'banner' || role === 'contentinfo' || role === 'complementary') return true;
+			const hint = (typeof node.className === 'string' ? node.className : '') + ' ' + (node.id || '');
+			return boilerplateRe.test(hint) && linkDensity(node) > 0.3;
+		};
+
+		const inline = (node) => Array.from(node.childNodes).map(convert).join('');
+
+		const block = (text) => '\n\n' + text.trim() + '\n\n';
+
+		const convert = (node) => {
+			if (node.nodeType === Node.TEXT_NODE) {
+				return node.textContent.replace(/\s+/g, ' ');
+			}
+			if (node.nodeType !== Node.ELEMENT_NODE) return '';
+
+			const tag = node.tagName.toLowerCase();
+			if (skipTags.has(tag) || hidden(node) || isBoilerplate(node)) return '';
+
+			switch (tag) {
+				case 'h1': case 'h2': case 'h3': case 'h4': case 'h5': case 'h6': {
+					const text = inline(node).trim();
+					return text ? block('#'.repeat(Number(tag[1])) + ' ' + text) : '';
+				}
+				case 'p': case 'div': case 'section': case 'article': case 'main': case 'figure': case 'figcaption': case 'dl':
+					return block(inline(node));
+				case 'dt':
+					return block('**' + inline(node).trim() + '**');
+				case 'dd':
+					return block(inline(node));
+				case 'br':
+					return '\n';
+				case 'hr':
+					return block('---');
+				case 'strong': case 'b': {
+					const text = inline(node).trim();
+					return text ? '**' + text + '** ' : '';
+				}
+				case 'em': case 'i': {
+					const text = inline(node).trim();
+					return text ? '*' + text + '* ' : '';
+				}
+				case 'code':
+					return '` + "`" + `' + node.textContent.trim() + '` + "`" + `';
+				case 'pre':
+					return block('` + "```" + `\n' + node.textContent.replace(/\n+$/, '') + '\n` + "```" + `');
+				case 'blockquote':
+					return block(inline(node).trim().split('\n').map(l => '> ' + l).join('\n'));
+				case 'a': {
+					const text = inline(node).trim();
+					const href = node.href || '';
+					if (!text) return '';
+					if (!href || href.startsWith('javascript:')) return text;
+					return '[' + text + '](' + href + ')';
+				}
+				case 'img': {
+					const alt = (node.getAttribute('alt') || '').trim();
+					return alt ? '![' + alt + '](' + node.src + ')' : '';
+				}
+				case 'ul': case 'ol': {
+					let n = 0;
+					const items = Array.from(node.children)
+						.filter(li => li.tagName.toLowerCase() === 'li' && !hidden(li))
+						.map(li => {
+							n++;
+							const text = inline(li).replace(/\n{2,}/g, '\n').trim().split('\n').join('\n  ');
+							return (tag === 'ol' ? n + '. ' : '- ') + text;
+						})
+						.filter(item => item.trim().length > 2);
+					return items.length ? block(items.join('\n')) : '';
+				}
+				case 'table': {
+					const rows = Array.from(node.querySelectorAll('tr'))
+						.map(tr => Array.from(tr.children)
+							.map(cell => inline(cell).replace(/\s+/g, ' ').replace(/\|/g, '\\|').trim()))
+						.filter(cells => cells.some(c => c));
+					if (!rows.length) return '';
+					const width = Math.max(...rows.map(r => r.length));
+					const line = (cells) => '| ' + Array.from({length: width}, (_, i) => cells[i] || '').join(' | ') + ' |';
+					const out = [line(rows[0]), '|' + ' --- |'.repeat(width)];
+					rows.slice(1).forEach(r => out.push(line(r)));
+					return block(out.join('\n'));
+				}
+				default:
+					return inline(node);
+			}
+		};
+
+		const markdown = convert(root)
+			.replace(/[ \t]+\n/g, '\n')
+			.replace(/\n{3,}/g, '\n\n')
+			.trim();
+
+		return {title: doc.title, scope: scope, markdown: markdown};
+	}`
+}
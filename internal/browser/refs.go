@@ -0,0 +1,120 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+var refSelectorPattern = regexp.MustCompile(`^\s*ref=(\d+)\s*$`)
+
+// elementRegistry holds the latest element snapshot of one page. doc is the
+// token of the document the ids were assigned in.
+type elementRegistry struct {
+	doc       string
+	elements  map[int]entity.Element
+	clickable []entity.Element
+}
+
+func (m *Manager) registerElements(doc string, elements []entity.Element) {
+	registry := &elementRegistry{
+		doc:       doc,
+		elements:  make(map[int]entity.Element, len(elements)),
+		clickable: clickableElements(elements),
+	}
+
+	for _, elem := range elements {
+		if elem.ID > 0 {
+			registry.elements[elem.ID] = elem
+		}
+	}
+
+	m.refsMu.Lock()
+	m.refs[m.page] = registry
+	m.refsMu.Unlock()
+}
+
+// addElements makes elements found outside the element list (e.g. by
+// find_text) addressable by id without dropping the listed ones.
+func (m *Manager) addElements(doc string, elements []entity.Element) {
+	m.refsMu.Lock()
+	defer m.refsMu.Unlock()
+
+	registry := m.refs[m.page]
+	if registry == nil || registry.doc != doc {
+		registry = &elementRegistry{
+			doc:      doc,
+			elements: make(map[int]entity.Element, len(elements)),
+		}
+		m.refs[m.page] = registry
+	}
+
+	for _, elem := range elements {
+		if elem.ID > 0 {
+			registry.elements[elem.ID] = elem
+		}
+	}
+}
+
+func (m *Manager) forgetElements(page playwright.Page) {
+	m.refsMu.Lock()
+	delete(m.refs, page)
+	m.refsMu.Unlock()
+}
+
+// resolveTarget turns an element reference (ref=17) into a selector for the
+// live element. Other selectors are returned unchanged.
+func (m *Manager) resolveTarget(op, selector string) (string, error) {
+	match := refSelectorPattern.FindStringSubmatch(selector)
+	if match == nil {
+		return selector, nil
+	}
+
+	id, _ := strconv.Atoi(match[1])
+
+	m.refsMu.Lock()
+	registry := m.refs[m.page]
+	m.refsMu.Unlock()
+
+	var elem entity.Element
+	var known bool
+
+	if registry != nil {
+		elem, known = registry.elements[id]
+	}
+
+	if !known {
+		return "", apperr.Wrap(op, apperr.CodeStaleElement, fmt.Errorf("element [%d] is not in the current element list of this tab", id), map[string]any{
+			apperr.MetaReason:   "unknown_element_id",
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	result, err := m.page.Evaluate(resolveRefScript(), map[string]interface{}{
+		"id":  id,
+		"doc": registry.doc,
+	})
+	if err != nil {
+		return "", apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "evaluate_failed",
+		})
+	}
+
+	resultMap, _ := result.(map[string]interface{})
+	if stale := getString(resultMap, "stale"); stale != "" {
+		return "", apperr.Wrap(op, apperr.CodeStaleElement, fmt.Errorf("element [%d] is stale: %s, use an id from the latest page state", id, stale), map[string]any{
+			apperr.MetaReason:   "stale_element",
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	parts := splitFrameSelector(elem.Selector)
+	parts[len(parts)-1] = fmt.Sprintf(`[data-agent-ref="%d"]`, id)
+
+	return strings.Join(parts, frameSeparator), nil
+}
@@ -0,0 +1,349 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultScrollRounds = 15
+	maxScrollRounds     = 50
+	staleScrollRounds   = 2
+)
+
+// scrollContainerJS declares findScroller, which picks the element to scroll:
+// the target or its nearest scrollable ancestor, otherwise the dominant
+// scrollable container under the viewport center (mail and chat panes,
+// modals), falling back to the page itself.
+const scrollContainerJS = `const isScrollable = (n) => {
+				if (!n || n.nodeType !== 1) return false;
+				const style = getComputedStyle(n);
+				return /(auto|scroll|overlay)/.test(style.overflowY) && n.scrollHeight > n.clientHeight + 1;
+			};
+			const page = document.scrollingElement || document.documentElement;
+			const pageScrollable = page.scrollHeight > window.innerHeight + 1;
+			const visibleArea = (n) => {
+				const r = n.getBoundingClientRect();
+				const w = Math.max(0, Math.min(r.right, window.innerWidth) - Math.max(r.left, 0));
+				const h = Math.max(0, Math.min(r.bottom, window.innerHeight) - Math.max(r.top, 0));
+				return w * h;
+			};
+			const findScroller = (el) => {
+				if (el) {
+					for (let n = el; n && n !== document.body && n !== document.documentElement; n = n.parentElement) {
+						if (isScrollable(n)) return n;
+					}
+					return page;
+				}
+
+				const viewport = window.innerWidth * window.innerHeight;
+				for (let n = document.elementFromPoint(window.innerWidth / 2, window.innerHeight / 2); n && n !== document.body; n = n.parentElement) {
+					if (isScrollable(n) && (visibleArea(n) >= viewport * 0.2 || !pageScrollable)) return n;
+				}
+				if (pageScrollable) return page;
+
+				let best = null;
+				let bestArea = 0;
+				for (const n of document.querySelectorAll('body *')) {
+					if (!isScrollable(n)) continue;
+					const area = visibleArea(n);
+					if (area > bestArea) {
+						best = n;
+						bestArea = area;
+					}
+				}
+				return best || page;
+			};
+			const describeScroller = (n) => {
+				if (n === page) return 'page';
+				let name = n.tagName.toLowerCase();
+				if (n.id) name += '#' + n.id;
+				else if (typeof n.className === 'string' && n.className.trim()) name += '.' + n.className.trim().split(/\s+/).slice(0, 2).join('.');
+				const label = n.getAttribute('aria-label') || n.getAttribute('role');
+				return label ? name + ' (' + label + ')' : name;
+			};
+			const scrollState = (n) => {
+				const max = n.scrollHeight - n.clientHeight;
+				return {
+					container: describeScroller(n),
+					top: Math.round(n.scrollTop),
+					height: n.scrollHeight,
+					atTop: n.scrollTop <= 1,
+					atBottom: n.scrollTop >= max - 2,
+					progress: max > 0 ? Math.round(100 * n.scrollTop / max) : 100
+				};
+			};`
+
+// evaluateScroll runs script on the container found for selector, or on the
+// auto-detected container when selector is empty.
+func (m *Manager) evaluateScroll(selector, script string, arg map[string]interface{}) (map[string]interface{}, error) {
+	var result interface{}
+	var err error
+
+	if selector == "" {
+		result, err = m.page.Evaluate(`(arg) => (`+script+`)(null, arg)`, arg)
+	} else {
+		result, err = m.locate(selector).Evaluate(script, arg, playwright.LocatorEvaluateOptions{
+			Timeout: playwright.Float(waitTimeout),
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	resultMap, _ := result.(map[string]interface{})
+
+	return resultMap, nil
+}
+
+func parseScrollResult(resultMap map[string]interface{}) *entity.ScrollResult {
+	return &entity.ScrollResult{
+		Container: getString(resultMap, "container"),
+		Moved:     getBool(resultMap, "moved"),
+		AtTop:     getBool(resultMap, "atTop"),
+		AtBottom:  getBool(resultMap, "atBottom"),
+		Progress:  int(getFloat(resultMap, "progress")),
+	}
+}
+
+func scrollScript() string {
+	return `(el, arg) => {
+		` + scrollContainerJS + `
+
+		const scroller = findScroller(el);
+		const before = scroller.scrollTop;
+		const top = {
+			down: before + arg.amount,
+			up: before - arg.amount,
+			bottom: scroller.scrollHeight,
+			top: 0
+		}[arg.direction];
+
+		scroller.scrollTo({top: top === undefined ? before + arg.amount : top, behavior: 'instant'});
+
+		const state = scrollState(scroller);
+		state.moved = Math.abs(scroller.scrollTop - before) >= 1;
+		return state;
+	}`
+}
+
+// ScrollUntil keeps scrolling a container (or the auto-detected one) to the
+// bottom until the condition holds: text appears, the selector matches at
+// least count elements, or, with only count, the list holds count items.
+// Without a condition, or when it is never met, it stops once the content
+// stops growing or after maxRounds.
+func (m *Manager) ScrollUntil(ctx context.Context, selector string, until entity.ScrollCondition, maxRounds int) (progress *entity.ScrollProgress, err error) {
+	const op = "ScrollUntil"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("selector", selector),
+		attribute.String("until_selector", until.Selector),
+		attribute.Int("until_count", until.Count))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return nil, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxRounds <= 0 {
+		maxRounds = defaultScrollRounds
+	}
+
+	maxRounds = min(maxRounds, maxScrollRounds)
+
+	arg := map[string]interface{}{
+		"text":     strings.TrimSpace(until.Text),
+		"selector": until.Selector,
+		"count":    until.Count,
+	}
+
+	progress = &entity.ScrollProgress{}
+	stale := 0
+	lastHeight := -1.0
+
+	for round := 0; ; round++ {
+		if err := ctx.Err(); err != nil {
+			return nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+				apperr.MetaReason:   "scroll_cancelled",
+				apperr.MetaStage:    apperr.StageInteraction,
+				apperr.MetaSelector: selector,
+			})
+		}
+
+		arg["scroll"] = round > 0
+
+		resultMap, err := m.evaluateScroll(selector, scrollUntilScript(), arg)
+		if err != nil {
+			return nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+				apperr.MetaReason:   "scroll_failed",
+				apperr.MetaStage:    apperr.StageInteraction,
+				apperr.MetaSelector: selector,
+			})
+		}
+
+		if msg := getString(resultMap, "error"); msg != "" {
+			return nil, apperr.InvalidReqError(op, "until_selector", fmt.Errorf("%s", msg))
+		}
+
+		progress.Container = getString(resultMap, "container")
+		progress.Rounds = round
+		progress.Items = int(getFloat(resultMap, "items"))
+		progress.AtBottom = getBool(resultMap, "atBottom")
+
+		if getBool(resultMap, "met") {
+			progress.Met = true
+			progress.Stopped = "condition met"
+
+			if match, ok := resultMap["match"].(map[string]interface{}); ok {
+				elem := parseElement(match)
+				progress.Match = &elem
+				m.addElements(getString(resultMap, "doc"), []entity.Element{elem})
+			}
+
+			break
+		}
+
+		// The first scroll is measured before anything could load, so
+		// growth is only judged from the second one on.
+		height := getFloat(resultMap, "height")
+		if round > 1 && height <= lastHeight && progress.AtBottom {
+			stale++
+		} else {
+			stale = 0
+		}
+
+		lastHeight = max(lastHeight, height)
+
+		if stale >= staleScrollRounds {
+			progress.Stopped = "content stopped growing"
+
+			break
+		}
+
+		if round >= maxRounds {
+			progress.Stopped = fmt.Sprintf("reached the limit of %d scrolls", maxRounds)
+
+			break
+		}
+
+		step.AddEvent("scrolling", attribute.Int("round", round+1))
+
+		if round > 0 {
+			m.settle(step)
+		}
+	}
+
+	if progress.Rounds > 0 || progress.Match != nil {
+		m.settle(step)
+	}
+
+	step.AddEvent("scroll finished",
+		attribute.Int("rounds", progress.Rounds),
+		attribute.Bool("met", progress.Met),
+		attribute.Int("items", progress.Items))
+
+	return progress, nil
+}
+
+// scrollUntilScript checks the condition and, when arg.scroll is set and it
+// is not met yet, scrolls the container to the bottom first. Items are the
+// children of the largest group of repeated siblings in the container.
+func scrollUntilScript() string {
+	return `(el, arg) => {
+		` + scrollContainerJS + `
+		` + elementRefsJS + `
+
+		const scroller = findScroller(el);
+		const root = scroller === page ? document.body : scroller;
+
+		if (arg.scroll) scroller.scrollTo({top: scroller.scrollHeight, behavior: 'instant'});
+
+		const countItems = () => {
+			let best = 0;
+			const nodes = root.querySelectorAll('*');
+			for (let i = 0; i < nodes.length && i < 20000; i++) {
+				const children = nodes[i].children;
+				if (children.length <= best) continue;
+				const tags = {};
+				for (const c of children) tags[c.tagName] = (tags[c.tagName] || 0) + 1;
+				best = Math.max(best, ...Object.values(tags));
+			}
+			return best;
+		};
+
+		const state = scrollState(scroller);
+		let met = !!(arg.text || arg.selector || arg.count > 0);
+		let match = null;
+		let items = 0;
+
+		// Every given condition must hold.
+		if (arg.selector) {
+			try {
+				items = root.querySelectorAll(arg.selector).length;
+			} catch (e) {
+				return {error: 'invalid CSS selector: ' + arg.selector};
+			}
+			met = items >= Math.max(arg.count, 1);
+		} else {
+			items = countItems();
+			if (arg.count > 0) met = items >= arg.count;
+		}
+
+		if (met && arg.text) {
+			met = false;
+			const query = arg.text.replace(/\s+/g, ' ').toLowerCase();
+			const walker = document.createTreeWalker(root, NodeFilter.SHOW_TEXT);
+			for (let node = walker.nextNode(); node; node = walker.nextNode()) {
+				const parent = node.parentElement;
+				if (!parent || ['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE'].includes(parent.tagName)) continue;
+				if (!node.textContent.replace(/\s+/g, ' ').toLowerCase().includes(query)) continue;
+				const r = parent.getBoundingClientRect();
+				if (r.width === 0 && r.height === 0) continue;
+
+				parent.scrollIntoView({behavior: 'instant', block: 'center'});
+				const box = parent.getBoundingClientRect();
+				const target = parent.closest('a, button, [role="button"], [role="link"], li, article, tr') || parent;
+				met = true;
+				match = {
+					id: refOf(target),
+					tag: target.tagName.toLowerCase(),
+					text: (target.innerText || '').replace(/\s+/g, ' ').trim().slice(0, 160),
+					selector: '*',
+					visible: true,
+					clickable: !!target.closest('a, button, [role="button"], [role="link"]'),
+					x: Math.round(box.left + box.width / 2),
+					y: Math.round(box.top + box.height / 2),
+					width: Math.round(box.width),
+					height: Math.round(box.height)
+				};
+				break;
+			}
+		}
+
+		return Object.assign(state, {doc: refs.doc, met: met, match: match, items: items});
+	}`
+}
@@ -0,0 +1,110 @@
+package browser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// frameSeparator joins the selectors of nested iframes with the selector of
+// the element inside the innermost one, e.g. `iframe[name="card"] >>> input`.
+// Open shadow roots need no special syntax, Playwright CSS pierces them.
+const frameSeparator = " >>> "
+
+func splitFrameSelector(selector string) []string {
+	parts := strings.Split(selector, strings.TrimSpace(frameSeparator))
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	return parts
+}
+
+func isFrameSelector(selector string) bool {
+	return strings.Contains(selector, strings.TrimSpace(frameSeparator))
+}
+
+// roleSelectorPattern matches role+name locators produced by the
+// accessibility page state, e.g. `role=button[name="Add to cart"] >> nth=1`.
+var roleSelectorPattern = regexp.MustCompile(`^role=([a-zA-Z]+)(?:\[name="((?:[^"\\]|\\.)*)"\])?(?:\s*>>\s*nth=(\d+))?$`)
+
+type roleSelector struct {
+	role  playwright.AriaRole
+	name  string
+	index int
+}
+
+func parseRoleSelector(selector string) (*roleSelector, bool) {
+	match := roleSelectorPattern.FindStringSubmatch(strings.TrimSpace(selector))
+	if match == nil {
+		return nil, false
+	}
+
+	rs := &roleSelector{
+		role: playwright.AriaRole(strings.ToLower(match[1])),
+		name: strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(match[2]),
+	}
+
+	if match[3] != "" {
+		rs.index, _ = strconv.Atoi(match[3])
+	}
+
+	return rs, true
+}
+
+func formatRoleSelector(role, name string, index int) string {
+	selector := "role=" + role
+	if name != "" {
+		selector += fmt.Sprintf(`[name="%s"]`, strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name))
+	}
+
+	if index > 0 {
+		selector += fmt.Sprintf(" >> nth=%d", index)
+	}
+
+	return selector
+}
+
+// locate resolves a possibly frame-aware selector through the right frame.
+// The innermost part may be a CSS selector or a role+name locator.
+func (m *Manager) locate(selector string) playwright.Locator {
+	parts := splitFrameSelector(selector)
+	target := parts[len(parts)-1]
+	rs, isRole := parseRoleSelector(target)
+
+	if len(parts) == 1 {
+		if isRole {
+			return m.page.GetByRole(rs.role, playwright.PageGetByRoleOptions{
+				Name:  roleName(rs),
+				Exact: playwright.Bool(rs.name != ""),
+			}).Nth(rs.index)
+		}
+
+		return m.page.Locator(selector).First()
+	}
+
+	frame := m.page.FrameLocator(parts[0])
+	for _, part := range parts[1 : len(parts)-1] {
+		frame = frame.FrameLocator(part)
+	}
+
+	if isRole {
+		return frame.GetByRole(rs.role, playwright.FrameLocatorGetByRoleOptions{
+			Name:  roleName(rs),
+			Exact: playwright.Bool(rs.name != ""),
+		}).Nth(rs.index)
+	}
+
+	return frame.Locator(target).First()
+}
+
+func roleName(rs *roleSelector) interface{} {
+	if rs.name == "" {
+		return nil
+	}
+
+	return rs.name
+}
@@ -0,0 +1,219 @@
+package browser
+
+import (
+	"ai-agent-task/pkg/tracing"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const settlePollInterval = 100 * time.Millisecond
+
+// inflightRequests counts the network requests of one page. Long-lived
+// streams never finish, so they are not counted.
+type inflightRequests struct {
+	mu       sync.Mutex
+	requests map[playwright.Request]struct{}
+}
+
+func newInflightRequests(p playwright.Page) *inflightRequests {
+	inflight := &inflightRequests{
+		requests: make(map[playwright.Request]struct{}),
+	}
+
+	p.OnRequest(func(r playwright.Request) {
+		switch r.ResourceType() {
+		case "websocket", "eventsource", "media":
+			return
+		}
+
+		inflight.mu.Lock()
+		inflight.requests[r] = struct{}{}
+		inflight.mu.Unlock()
+	})
+	p.OnRequestFinished(inflight.done)
+	p.OnRequestFailed(inflight.done)
+
+	return inflight
+}
+
+func (i *inflightRequests) done(r playwright.Request) {
+	i.mu.Lock()
+	delete(i.requests, r)
+	i.mu.Unlock()
+}
+
+func (i *inflightRequests) count() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return len(i.requests)
+}
+
+// settleState is what the page reports about itself while settling.
+type settleState struct {
+	pending    int
+	quietFor   time.Duration
+	animations int
+}
+
+// waitForSettle blocks until the active page is quiet: no requests in flight,
+// no pending fetch/XHR, no DOM mutations for the quiet window and no finite
+// CSS animations running. It gives up after the configured max wait and
+// reports whether the page settled.
+func (m *Manager) waitForSettle() (time.Duration, bool) {
+	started := time.Now()
+	maxWait := time.Duration(m.config.BrowserConfig.SettleTimeout) * time.Millisecond
+	quiet := time.Duration(m.config.BrowserConfig.SettleQuiet) * time.Millisecond
+	page := m.page
+
+	if page == nil || page.IsClosed() || maxWait <= 0 {
+		return 0, true
+	}
+
+	_ = page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
+		State:   playwright.LoadStateDomcontentloaded,
+		Timeout: playwright.Float(float64(maxWait.Milliseconds())),
+	})
+
+	var inflight *inflightRequests
+	if t := m.tabForPage(page); t != nil {
+		inflight = t.inflight
+	}
+
+	for {
+		if m.pendingDialogOnActivePage() || page.IsClosed() {
+			return time.Since(started), true
+		}
+
+		state, err := m.probeSettle(page)
+		if err != nil {
+			m.logger.Debug("Settle probe failed", zap.Error(err))
+		}
+
+		requests := 0
+		if inflight != nil {
+			requests = inflight.count()
+		}
+
+		if err == nil && requests == 0 && state.pending == 0 && state.animations == 0 && state.quietFor >= quiet {
+			return time.Since(started), true
+		}
+
+		if time.Since(started)+settlePollInterval > maxWait {
+			m.logger.Debug("Page did not settle",
+				zap.Int("requests", requests),
+				zap.Int("pending_fetch", state.pending),
+				zap.Int("animations", state.animations),
+				zap.Duration("quiet_for", state.quietFor))
+
+			return time.Since(started), false
+		}
+
+		time.Sleep(settlePollInterval)
+	}
+}
+
+func (m *Manager) probeSettle(page playwright.Page) (settleState, error) {
+	result, err := page.Evaluate(settleProbeScript())
+	if err != nil {
+		return settleState{}, err
+	}
+
+	resultMap, _ := result.(map[string]interface{})
+
+	if installed, _ := resultMap["installed"].(bool); !installed {
+		// Documents loaded before the init script was registered.
+		if _, err := page.Evaluate(settleInitScript); err != nil {
+			return settleState{}, err
+		}
+
+		return settleState{}, nil
+	}
+
+	return settleState{
+		pending:    int(getFloat(resultMap, "pending")),
+		quietFor:   time.Duration(getFloat(resultMap, "quiet")) * time.Millisecond,
+		animations: int(getFloat(resultMap, "animations")),
+	}, nil
+}
+
+func (m *Manager) tabForPage(p playwright.Page) *tab {
+	m.tabsMu.Lock()
+	defer m.tabsMu.Unlock()
+
+	for _, t := range m.tabs {
+		if t.page == p {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// settleInitScript runs in every document before the site's own scripts and
+// keeps the counters the settle probe reads.
+const settleInitScript = `(() => {
+	if (window.__agentSettle) return;
+
+	const s = window.__agentSettle = {pending: 0, lastMutation: performance.now()};
+	const done = () => { s.pending = Math.max(0, s.pending - 1); };
+
+	if (window.fetch) {
+		const fetch = window.fetch;
+		window.fetch = function (...args) {
+			s.pending++;
+			try {
+				const p = fetch.apply(this, args);
+				p.then(done, done);
+				return p;
+			} catch (e) {
+				done();
+				throw e;
+			}
+		};
+	}
+
+	const send = XMLHttpRequest.prototype.send;
+	XMLHttpRequest.prototype.send = function (...args) {
+		s.pending++;
+		this.addEventListener('loadend', done, {once: true});
+		return send.apply(this, args);
+	};
+
+	new MutationObserver(() => { s.lastMutation = performance.now(); })
+		.observe(document, {subtree: true, childList: true, characterData: true});
+})()`
+
+func settleProbeScript() string {
+	return `() => {
+		const s = window.__agentSettle;
+		if (!s) return {installed: false};
+
+		let animations = 0;
+		if (document.getAnimations) {
+			for (const a of document.getAnimations()) {
+				if (a.playState !== 'running') continue;
+				const end = a.effect && a.effect.getComputedTiming().endTime;
+				if (Number.isFinite(end)) animations++;
+			}
+		}
+
+		return {
+			installed: true,
+			pending: s.pending,
+			quiet: performance.now() - s.lastMutation,
+			animations: animations
+		};
+	}`
+}
+
+func (m *Manager) settle(step *tracing.Span) {
+	waited, settled := m.waitForSettle()
+	step.AddEvent("page settled",
+		attribute.Int64("settle_ms", waited.Milliseconds()),
+		attribute.Bool("settled", settled))
+}
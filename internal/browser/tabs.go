@@ -0,0 +1,314 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+type tab struct {
+	id       int
+	page     playwright.Page
+	inflight *inflightRequests
+}
+
+func (m *Manager) trackContext() {
+	if err := m.browserContext.AddInitScript(playwright.Script{
+		Content: playwright.String(settleInitScript),
+	}); err != nil {
+		m.logger.Warn("Failed to add settle script", zap.Error(err))
+	}
+
+	for _, p := range m.browserContext.Pages() {
+		m.trackPage(p, false)
+	}
+
+	m.browserContext.OnPage(func(p playwright.Page) {
+		m.trackPage(p, true)
+	})
+}
+
+// trackPage registers a page as a tab. Pages opened by the site (target=_blank
+// links, OAuth popups) are announced to the agent, pages the manager opened
+// itself are not.
+func (m *Manager) trackPage(p playwright.Page, announce bool) *tab {
+	m.tabsMu.Lock()
+	defer m.tabsMu.Unlock()
+
+	for _, t := range m.tabs {
+		if t.page == p {
+			if !announce {
+				m.forgetNewTabLocked(t.id)
+			}
+
+			return t
+		}
+	}
+
+	m.nextTabID++
+	t := &tab{id: m.nextTabID, page: p, inflight: newInflightRequests(p)}
+	m.tabs = append(m.tabs, t)
+
+	if announce {
+		m.newTabs = append(m.newTabs, t.id)
+		m.logger.Info("New tab opened", zap.Int("tab_id", t.id), zap.String(logg.URL, p.URL()))
+	}
+
+	p.OnDialog(m.onDialog(t))
+	p.OnFileChooser(m.onFileChooser)
+	p.OnDownload(m.onDownload)
+	p.OnClose(func(closed playwright.Page) {
+		m.untrackPage(closed)
+	})
+
+	return t
+}
+
+func (m *Manager) untrackPage(p playwright.Page) {
+	m.tabsMu.Lock()
+	defer m.tabsMu.Unlock()
+
+	for i, t := range m.tabs {
+		if t.page == p {
+			m.tabs = append(m.tabs[:i], m.tabs[i+1:]...)
+			m.forgetNewTabLocked(t.id)
+			m.forgetElements(p)
+
+			return
+		}
+	}
+}
+
+// forgetNewTabLocked expects tabsMu to be held.
+func (m *Manager) forgetNewTabLocked(id int) {
+	for i, newID := range m.newTabs {
+		if newID == id {
+			m.newTabs = append(m.newTabs[:i], m.newTabs[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func (m *Manager) findTab(id int) *tab {
+	m.tabsMu.Lock()
+	defer m.tabsMu.Unlock()
+
+	for _, t := range m.tabs {
+		if t.id == id {
+			return t
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) lastOpenTab() *tab {
+	m.tabsMu.Lock()
+	defer m.tabsMu.Unlock()
+
+	for i := len(m.tabs) - 1; i >= 0; i-- {
+		if !m.tabs[i].page.IsClosed() {
+			return m.tabs[i]
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) describeTab(t *tab) entity.Tab {
+	title, _ := t.page.Title()
+
+	return entity.Tab{
+		ID:     t.id,
+		URL:    t.page.URL(),
+		Title:  title,
+		Active: t.page == m.page,
+	}
+}
+
+func (m *Manager) ListTabs(ctx context.Context) (tabs []entity.Tab, err error) {
+	const op = "ListTabs"
+	logger := m.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op)
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return nil, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	m.tabsMu.Lock()
+	snapshot := make([]*tab, len(m.tabs))
+	copy(snapshot, m.tabs)
+	m.tabsMu.Unlock()
+
+	tabs = make([]entity.Tab, 0, len(snapshot))
+
+	for _, t := range snapshot {
+		if t.page.IsClosed() {
+			continue
+		}
+
+		tabs = append(tabs, m.describeTab(t))
+	}
+
+	return tabs, nil
+}
+
+func (m *Manager) SwitchTab(ctx context.Context, id int) (err error) {
+	const op = "SwitchTab"
+	logger := m.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op, attribute.Int("tab_id", id))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	t := m.findTab(id)
+	if t == nil || t.page.IsClosed() {
+		return apperr.NotFoundError(op, fmt.Errorf("tab %d not found", id))
+	}
+
+	if err := t.page.BringToFront(); err != nil {
+		logger.Warn("Failed to bring tab to front", zap.Error(err))
+	}
+
+	m.page = t.page
+	m.forgetNewTab(id)
+	step.AddEvent("tab switched")
+
+	return nil
+}
+
+func (m *Manager) CloseTab(ctx context.Context, id int) (err error) {
+	const op = "CloseTab"
+	logger := m.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op, attribute.Int("tab_id", id))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	t := m.findTab(id)
+	if t == nil || t.page.IsClosed() {
+		return apperr.NotFoundError(op, fmt.Errorf("tab %d not found", id))
+	}
+
+	wasActive := t.page == m.page
+
+	if err := t.page.Close(); err != nil {
+		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "close_tab_failed",
+			apperr.MetaStage:  apperr.StageBrowser,
+		})
+	}
+
+	m.untrackPage(t.page)
+
+	if wasActive {
+		m.page = nil
+
+		if err := m.ensurePageActive(ctx); err != nil {
+			return apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+				apperr.MetaReason: "page_not_active",
+			})
+		}
+
+		if err := m.page.BringToFront(); err != nil {
+			logger.Warn("Failed to bring tab to front", zap.Error(err))
+		}
+	}
+
+	step.AddEvent("tab closed")
+
+	return nil
+}
+
+func (m *Manager) NewTab(ctx context.Context, url string) (id int, err error) {
+	const op = "NewTab"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.URL, url))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op, attribute.String("url", url))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return 0, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	page, err := m.browserContext.NewPage()
+	if err != nil {
+		return 0, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "new_page_failed",
+			apperr.MetaStage:  apperr.StageBrowser,
+		})
+	}
+
+	t := m.trackPage(page, false)
+	m.page = page
+
+	if url != "" {
+		step.AddEvent("navigating new tab")
+
+		if err := m.Navigate(ctx, url); err != nil {
+			return t.id, err
+		}
+	}
+
+	return t.id, nil
+}
+
+// DrainNewTabs returns tabs opened by the page since the previous call.
+func (m *Manager) DrainNewTabs() []entity.Tab {
+	m.tabsMu.Lock()
+	ids := m.newTabs
+	m.newTabs = nil
+	m.tabsMu.Unlock()
+
+	tabs := make([]entity.Tab, 0, len(ids))
+
+	for _, id := range ids {
+		if t := m.findTab(id); t != nil && !t.page.IsClosed() {
+			_ = t.page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
+				State:   playwright.LoadStateDomcontentloaded,
+				Timeout: playwright.Float(3000),
+			})
+
+			tabs = append(tabs, m.describeTab(t))
+		}
+	}
+
+	return tabs
+}
+
+func (m *Manager) forgetNewTab(id int) {
+	m.tabsMu.Lock()
+	defer m.tabsMu.Unlock()
+
+	m.forgetNewTabLocked(id)
+}
@@ -0,0 +1,344 @@
+package browser
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const (
+	maxTypingDelay     = 1000
+	maxSuggestions     = 10
+	suggestionWait     = 1200 * time.Millisecond
+	suggestionInterval = 200 * time.Millisecond
+)
+
+var keyAliases = map[string]string{
+	"ctrl":    "Control",
+	"cmd":     "Meta",
+	"command": "Meta",
+	"option":  "Alt",
+	"esc":     "Escape",
+	"return":  "Enter",
+	"del":     "Delete",
+	"space":   "Space",
+	"up":      "ArrowUp",
+	"down":    "ArrowDown",
+	"left":    "ArrowLeft",
+	"right":   "ArrowRight",
+}
+
+// normalizeKey maps common spellings (ctrl+a, Cmd+Shift+K) to Playwright key
+// names. Single characters keep their case.
+func normalizeKey(key string) string {
+	parts := strings.Split(key, "+")
+
+	for i, part := range parts {
+		if alias, ok := keyAliases[strings.ToLower(part)]; ok {
+			parts[i] = alias
+		} else if len(part) > 1 {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+
+	return strings.Join(parts, "+")
+}
+
+// TypeText types segments key by key like a person would, with a jittered
+// delay around delay ms per character (BrowserConfig.TypingDelay when zero).
+// Key segments are pressed as keys or chords. With a selector the
+// element is clicked first, otherwise the focused element receives the keys;
+// clear empties the field before typing. Autocomplete suggestions that show
+// up afterwards are returned with element ids.
+func (m *Manager) TypeText(ctx context.Context, selector string, segments []entity.TypingSegment, delay int, clear bool) (typed *entity.TypingResult, err error) {
+	const op = "TypeText"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("selector", selector),
+		attribute.Int("delay", delay),
+		attribute.Bool("clear", clear))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return nil, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if delay <= 0 {
+		delay = m.config.BrowserConfig.TypingDelay
+	}
+
+	delay = min(delay, maxTypingDelay)
+	keyboard := m.page.Keyboard()
+
+	if selector != "" {
+		step.AddEvent("focusing element")
+
+		locator := m.locate(selector)
+
+		err = m.untilDialog(func() error {
+			return locator.Click(playwright.LocatorClickOptions{
+				Timeout: playwright.Float(clickTimeout),
+			})
+		})
+		if err != nil {
+			return nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+				apperr.MetaReason:   "focus_failed",
+				apperr.MetaStage:    apperr.StageInteraction,
+				apperr.MetaSelector: selector,
+			})
+		}
+
+		if clear {
+			step.AddEvent("clearing field")
+
+			err := locator.Clear(playwright.LocatorClearOptions{
+				Timeout: playwright.Float(clickTimeout),
+			})
+			if err == nil {
+				clear = false
+			} else {
+				logger.Warn("Clear failed, selecting and deleting instead", zap.Error(err))
+			}
+		}
+	}
+
+	// Without a selector, or when the element refused Clear (custom
+	// editors), select everything and delete it with the keyboard.
+	if clear {
+		if err := keyboard.Press("ControlOrMeta+A"); err == nil {
+			keyboard.Press("Backspace")
+		}
+	}
+
+	step.AddEvent("typing", attribute.Int("segments", len(segments)))
+
+	err = m.untilDialog(func() error {
+		for _, segment := range segments {
+			if segment.Key != "" {
+				key := normalizeKey(segment.Key)
+
+				if err := keyboard.Press(key); err != nil {
+					return fmt.Errorf("press %s: %w", key, err)
+				}
+
+				pause(delay)
+
+				continue
+			}
+
+			for _, r := range segment.Text {
+				if err := keyboard.Type(string(r)); err != nil {
+					return err
+				}
+
+				pause(delay)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "type_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	m.settle(step)
+
+	step.AddEvent("waiting for suggestions")
+
+	typed = m.suggestions()
+
+	step.AddEvent("typing completed", attribute.Int("suggestions", len(typed.Suggestions)))
+
+	return typed, nil
+}
+
+// pause sleeps for delay ms give or take half of it, so keystrokes do not
+// arrive at a machine-like fixed rhythm.
+func pause(delay int) {
+	if delay <= 0 {
+		return
+	}
+
+	time.Sleep(time.Duration(delay/2+rand.IntN(delay+1)) * time.Millisecond)
+}
+
+// suggestions polls briefly for an autocomplete popup attached to the focused
+// field, since many widgets debounce input before fetching suggestions.
+func (m *Manager) suggestions() *entity.TypingResult {
+	typed := &entity.TypingResult{}
+	deadline := time.Now().Add(suggestionWait)
+
+	for {
+		result, err := m.page.Evaluate(suggestionsScript(), map[string]interface{}{
+			"limit": maxSuggestions,
+		})
+		if err != nil {
+			m.logger.Warn("Failed to read suggestions", zap.Error(err))
+
+			return typed
+		}
+
+		resultMap, _ := result.(map[string]interface{})
+		typed.Value = getString(resultMap, "value")
+		typed.Suggestions = typed.Suggestions[:0]
+
+		var elements []entity.Element
+
+		if items, ok := resultMap["suggestions"].([]interface{}); ok {
+			for _, item := range items {
+				if itemMap, ok := item.(map[string]interface{}); ok {
+					elem := parseElement(itemMap)
+					elements = append(elements, elem)
+					typed.Suggestions = append(typed.Suggestions, elem)
+				}
+			}
+		}
+
+		if len(elements) > 0 {
+			m.addElements(getString(resultMap, "doc"), elements)
+
+			return typed
+		}
+
+		if time.Now().After(deadline) {
+			return typed
+		}
+
+		time.Sleep(suggestionInterval)
+	}
+}
+
+func suggestionsScript() string {
+	return `(arg) => {
+		` + elementRefsJS + `
+		` + frameSelectorJS + `
+		` + sensitiveInputJS + `
+
+		// Follow focus into same-origin iframes and open shadow roots.
+		let doc = document;
+		let active = document.activeElement;
+		const frames = [];
+		let offX = 0;
+		let offY = 0;
+		for (;;) {
+			if (active && active.shadowRoot && active.shadowRoot.activeElement) {
+				active = active.shadowRoot.activeElement;
+				continue;
+			}
+			if (active && /^i?frame$/i.test(active.tagName)) {
+				let inner = null;
+				try { inner = active.contentDocument; } catch (e) {}
+				if (!inner) break;
+				const r = active.getBoundingClientRect();
+				offX += r.left + active.clientLeft;
+				offY += r.top + active.clientTop;
+				frames.push(frameSelector(active));
+				doc = inner;
+				active = inner.activeElement;
+				continue;
+			}
+			break;
+		}
+
+		const norm = (s) => (s || '').replace(/\s+/g, ' ').trim();
+		const visible = (el) => {
+			const style = el.ownerDocument.defaultView.getComputedStyle(el);
+			if (style.display === 'none' || style.visibility === 'hidden') return false;
+			const r = el.getBoundingClientRect();
+			return r.width > 0 && r.height > 0;
+		};
+
+		let value = '';
+		if (active && 'value' in active && !isSensitive(active)) value = String(active.value);
+		else if (active && active.isContentEditable) value = norm(active.innerText);
+
+		// Popups linked to the field first, then common listbox and
+		// suggestion containers anywhere in the document.
+		const containers = [];
+		if (active) {
+			for (const attr of ['aria-controls', 'aria-owns', 'list']) {
+				for (const id of (active.getAttribute(attr) || '').split(/\s+/)) {
+					const el = id && doc.getElementById(id);
+					if (el) containers.push(el);
+				}
+			}
+		}
+		if (active && active.matches('input, textarea, [contenteditable], [role="combobox"], [role="searchbox"]')) {
+			doc.querySelectorAll('[role="listbox"], [class*="suggest" i], [class*="autocomplete" i], [class*="typeahead" i], [class*="combobox" i]').forEach(el => containers.push(el));
+		}
+
+		const items = [];
+		const seen = new Set();
+		for (const container of containers) {
+			if (container === active || container.contains(active)) continue;
+			if (container.tagName === 'DATALIST') {
+				for (const option of container.options) {
+					if (items.length >= arg.limit) break;
+					const text = norm(option.value || option.label);
+					if (text && !seen.has(text)) {
+						seen.add(text);
+						items.push({tag: 'option', text: text, visible: false, clickable: false, attributes: {source: 'datalist'}});
+					}
+				}
+				continue;
+			}
+			if (!visible(container)) continue;
+
+			let options = Array.from(container.querySelectorAll('[role="option"], li, a'));
+			if (!options.length) options = Array.from(container.children);
+			for (const el of options) {
+				if (items.length >= arg.limit) break;
+				if (seen.has(el) || !visible(el)) continue;
+				if (el.querySelector('[role="option"], li')) continue;
+				const text = norm(el.innerText || el.textContent);
+				if (!text || text.length > 200) continue;
+				seen.add(el);
+
+				const r = el.getBoundingClientRect();
+				items.push({
+					id: refOf(el),
+					tag: el.tagName.toLowerCase(),
+					text: text,
+					selector: frames.concat(['*']).join(' >>> '),
+					visible: true,
+					clickable: true,
+					x: Math.round(offX + r.left + r.width / 2),
+					y: Math.round(offY + r.top + r.height / 2),
+					width: Math.round(r.width),
+					height: Math.round(r.height),
+					attributes: el.getAttribute('aria-selected') === 'true' ? {'aria-selected': 'true'} : {}
+				});
+			}
+		}
+
+		return {doc: refs.doc, value: value, suggestions: items};
+	}`
+}
@@ -0,0 +1,154 @@
+package browser
+
+import (
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const isFileInputScript = `el => el.tagName === 'INPUT' && el.type === 'file'`
+
+// onFileChooser keeps file choosers opened by ordinary clicks. Listening for
+// the event also stops the native OS dialog from blocking a headed browser.
+func (m *Manager) onFileChooser(chooser playwright.FileChooser) {
+	if m.uploading.Load() {
+		return
+	}
+
+	m.uploadMu.Lock()
+	m.pendingChooser = chooser
+	m.uploadMu.Unlock()
+
+	m.logger.Info("File chooser opened", zap.Bool("multiple", chooser.IsMultiple()))
+}
+
+func (m *Manager) takeFileChooser() playwright.FileChooser {
+	m.uploadMu.Lock()
+	defer m.uploadMu.Unlock()
+
+	chooser := m.pendingChooser
+	m.pendingChooser = nil
+
+	if chooser == nil || chooser.Page().IsClosed() {
+		return nil
+	}
+
+	return chooser
+}
+
+func (m *Manager) fileChooserOpen() bool {
+	m.uploadMu.Lock()
+	defer m.uploadMu.Unlock()
+
+	return m.pendingChooser != nil && m.pendingChooser.Page() == m.page
+}
+
+// UploadFile attaches files to a file input, to the chooser opened by
+// clicking selector, or, with an empty selector, to an already open chooser.
+func (m *Manager) UploadFile(ctx context.Context, selector string, paths []string) (err error) {
+	const op = "UploadFile"
+	logger := m.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, selector))
+
+	ctx, step := tracing.StartSpan(ctx, m.tracer, logger, op,
+		attribute.String("selector", selector),
+		attribute.Int("files_count", len(paths)))
+	defer func() {
+		step.End(err)
+	}()
+
+	if !m.ready {
+		return apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
+	}
+
+	if err := m.ensurePageActive(ctx); err != nil {
+		return apperr.Wrap(op, apperr.CodeBrowserNotReady, err, map[string]any{
+			apperr.MetaReason: "page_not_active",
+		})
+	}
+
+	selector, err = m.resolveTarget(op, selector)
+	if err != nil {
+		return err
+	}
+
+	if selector == "" {
+		chooser := m.takeFileChooser()
+		if chooser == nil {
+			return apperr.InvalidReqError(op, "selector", fmt.Errorf("no file chooser is open, pass the upload input or button selector"))
+		}
+
+		step.AddEvent("setting files on open chooser")
+
+		return m.setChooserFiles(op, chooser, paths)
+	}
+
+	locator := m.locate(selector)
+
+	isInput, err := locator.Evaluate(isFileInputScript, nil, playwright.LocatorEvaluateOptions{
+		Timeout: playwright.Float(waitTimeout),
+	})
+	if err != nil {
+		return apperr.Wrap(op, apperr.CodeNotFound, err, map[string]any{
+			apperr.MetaReason:   "element_not_found",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	if ok, _ := isInput.(bool); ok {
+		step.AddEvent("setting input files")
+
+		if err := locator.SetInputFiles(paths); err != nil {
+			return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+				apperr.MetaReason:   "set_input_files_failed",
+				apperr.MetaStage:    apperr.StageInteraction,
+				apperr.MetaSelector: selector,
+			})
+		}
+
+		m.settle(step)
+
+		return nil
+	}
+
+	step.AddEvent("clicking and waiting for file chooser")
+
+	m.uploading.Store(true)
+	defer m.uploading.Store(false)
+
+	chooser, err := m.page.ExpectFileChooser(func() error {
+		return locator.Click(playwright.LocatorClickOptions{
+			Timeout: playwright.Float(clickTimeout),
+		})
+	}, playwright.PageExpectFileChooserOptions{
+		Timeout: playwright.Float(waitTimeout),
+	})
+	if err != nil {
+		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "file_chooser_not_opened",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: selector,
+		})
+	}
+
+	return m.setChooserFiles(op, chooser, paths)
+}
+
+func (m *Manager) setChooserFiles(op string, chooser playwright.FileChooser, paths []string) error {
+	if err := chooser.SetFiles(paths); err != nil {
+		return apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "set_files_failed",
+			apperr.MetaStage:  apperr.StageInteraction,
+		})
+	}
+
+	m.waitForSettle()
+
+	return nil
+}
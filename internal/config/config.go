@@ -2,15 +2,22 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
-	AppConfig     *AppConfig
-	AIConfig      *AIConfig
-	BrowserConfig *BrowserConfig
+	AppConfig        *AppConfig
+	AIConfig         *AIConfig
+	BrowserConfig    *BrowserConfig
+	AgentConfig      *AgentConfig
+	NavigationConfig *NavigationConfig
+	SecretsConfig    *SecretsConfig
+	RedactionConfig  *RedactionConfig
+	FilesConfig      *FilesConfig
 }
 
 type AppConfig struct {
@@ -25,11 +32,53 @@ type AIConfig struct {
 }
 
 type BrowserConfig struct {
-	Headless       bool   `envconfig:"BROWSER_HEADLESS" default:"false"`
-	SlowMo         int    `envconfig:"BROWSER_SLOW_MO" default:"100"`
-	Timeout        int    `envconfig:"BROWSER_TIMEOUT" default:"30000"`
-	UserDataDir    string `envconfig:"BROWSER_USER_DATA_DIR" default:"./browser-data"`
-	UseScreenshots bool   `envconfig:"BROWSER_USE_SCREENSHOTS" default:"true"`
+	Headless           bool   `envconfig:"BROWSER_HEADLESS" default:"false"`
+	SlowMo             int    `envconfig:"BROWSER_SLOW_MO" default:"100"`
+	Timeout            int    `envconfig:"BROWSER_TIMEOUT" default:"30000"`
+	UserDataDir        string `envconfig:"BROWSER_USER_DATA_DIR" default:"./browser-data"`
+	UseScreenshots     bool   `envconfig:"BROWSER_USE_SCREENSHOTS" default:"true"`
+	DialogBeforeUnload string `envconfig:"BROWSER_DIALOG_BEFOREUNLOAD" default:"dismiss"`
+	DialogAlert        string `envconfig:"BROWSER_DIALOG_ALERT" default:"accept"`
+	PageState          string `envconfig:"BROWSER_PAGE_STATE" default:"dom"`
+	ScreenshotMarks    bool   `envconfig:"BROWSER_SCREENSHOT_MARKS" default:"false"`
+	SettleTimeout      int    `envconfig:"BROWSER_SETTLE_TIMEOUT" default:"5000"`
+	SettleQuiet        int    `envconfig:"BROWSER_SETTLE_QUIET" default:"300"`
+	ReadChunkChars     int    `envconfig:"BROWSER_READ_CHUNK_CHARS" default:"6000"`
+	TypingDelay        int    `envconfig:"BROWSER_TYPING_DELAY" default:"60"`
+}
+
+type AgentConfig struct {
+	LoopWindow int    `envconfig:"AGENT_LOOP_WINDOW" default:"8"`
+	StuckLimit int    `envconfig:"AGENT_STUCK_LIMIT" default:"3"`
+	PolicyFile string `envconfig:"AGENT_POLICY_FILE"`
+	DryRun     bool   `envconfig:"AGENT_DRY_RUN" default:"false"`
+}
+
+type NavigationConfig struct {
+	AllowedDomains     []string `envconfig:"NAV_ALLOWED_DOMAINS"`
+	DeniedDomains      []string `envconfig:"NAV_DENIED_DOMAINS"`
+	AllowedURLPatterns []string `envconfig:"NAV_ALLOWED_URL_PATTERNS"`
+	DeniedURLPatterns  []string `envconfig:"NAV_DENIED_URL_PATTERNS"`
+	BlockRequests      bool     `envconfig:"NAV_BLOCK_REQUESTS" default:"true"`
+}
+
+type SecretsConfig struct {
+	File      string `envconfig:"SECRETS_FILE" default:"./secrets.enc"`
+	Key       string `envconfig:"SECRETS_KEY"`
+	EnvPrefix string `envconfig:"SECRETS_ENV_PREFIX" default:"AGENT_SECRET_"`
+}
+
+type RedactionConfig struct {
+	Detectors       []string `envconfig:"REDACT_DETECTORS" default:"email,phone,card,url_token,token"`
+	PageText        bool     `envconfig:"REDACT_PAGE_TEXT" default:"false"`
+	BlurScreenshots bool     `envconfig:"REDACT_BLUR_SCREENSHOTS" default:"false"`
+}
+
+type FilesConfig struct {
+	UploadDir       string `envconfig:"FILES_UPLOAD_DIR" default:"./uploads"`
+	DownloadDir     string `envconfig:"FILES_DOWNLOAD_DIR" default:"./downloads"`
+	ExtractText     bool   `envconfig:"FILES_EXTRACT_TEXT" default:"true"`
+	ExtractMaxChars int    `envconfig:"FILES_EXTRACT_MAX_CHARS" default:"4000"`
 }
 
 func GetConfig() (*Config, error) {
@@ -41,5 +90,41 @@ func GetConfig() (*Config, error) {
 		return nil, fmt.Errorf("read config from env vars: %w", err)
 	}
 
+	if err := conf.BrowserConfig.validate(); err != nil {
+		return nil, err
+	}
+
 	return &conf, nil
 }
+
+var (
+	dialogOutcomes = []string{"accept", "dismiss", "ask"}
+	pageStates     = []string{"dom", "accessibility"}
+)
+
+func (c *BrowserConfig) validate() error {
+	for _, setting := range []struct {
+		env   string
+		value *string
+	}{
+		{"BROWSER_DIALOG_BEFOREUNLOAD", &c.DialogBeforeUnload},
+		{"BROWSER_DIALOG_ALERT", &c.DialogAlert},
+	} {
+		if err := oneOf(setting.env, setting.value, dialogOutcomes); err != nil {
+			return err
+		}
+	}
+
+	return oneOf("BROWSER_PAGE_STATE", &c.PageState, pageStates)
+}
+
+// oneOf normalizes value and checks it is one of allowed.
+func oneOf(env string, value *string, allowed []string) error {
+	*value = strings.ToLower(strings.TrimSpace(*value))
+
+	if !slices.Contains(allowed, *value) {
+		return fmt.Errorf("invalid %s %q: must be one of %s", env, *value, strings.Join(allowed, ", "))
+	}
+
+	return nil
+}
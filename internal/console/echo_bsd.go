@@ -0,0 +1,20 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package console
+
+import "golang.org/x/sys/unix"
+
+func setEcho(fd int, on bool) error {
+	termios, err := unix.IoctlGetTermios(fd, unix.TIOCGETA)
+	if err != nil {
+		return err
+	}
+
+	if on {
+		termios.Lflag |= unix.ECHO
+	} else {
+		termios.Lflag &^= unix.ECHO
+	}
+
+	return unix.IoctlSetTermios(fd, unix.TIOCSETA, termios)
+}
@@ -0,0 +1,18 @@
+package console
+
+import "golang.org/x/sys/unix"
+
+func setEcho(fd int, on bool) error {
+	termios, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return err
+	}
+
+	if on {
+		termios.Lflag |= unix.ECHO
+	} else {
+		termios.Lflag &^= unix.ECHO
+	}
+
+	return unix.IoctlSetTermios(fd, unix.TCSETS, termios)
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package console
+
+import "errors"
+
+func setEcho(fd int, on bool) error {
+	return errors.New("hiding terminal input is not supported on this platform")
+}
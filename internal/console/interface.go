@@ -2,14 +2,17 @@ package console
 
 import (
 	"ai-agent-task/internal/config"
+	"ai-agent-task/internal/secrets"
 	"ai-agent-task/internal/usecase"
+	"ai-agent-task/pkg/jsonschema"
 	"ai-agent-task/pkg/logg"
-	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
 	"strings"
+	"sync/atomic"
 	"syscall"
 
 	"go.uber.org/fx"
@@ -17,21 +20,27 @@ import (
 )
 
 type Interface struct {
-	config   *config.Config
-	logger   *zap.Logger
-	usecase  *usecase.Service
-	ctx      context.Context
-	cancel   context.CancelFunc
-	sigChan  chan os.Signal
-	stopping bool
+	config       *config.Config
+	logger       *zap.Logger
+	usecase      *usecase.Service
+	secrets      *secrets.Vault
+	operator     *Operator
+	ctx          context.Context
+	cancel       context.CancelFunc
+	sigChan      chan os.Signal
+	stopping     bool
+	taskRunning  atomic.Bool
+	resultSchema map[string]interface{}
 }
 
 type Params struct {
 	fx.In
 
-	Config  *config.Config
-	Logger  *zap.Logger
-	Usecase *usecase.Service
+	Config   *config.Config
+	Logger   *zap.Logger
+	Usecase  *usecase.Service
+	Secrets  *secrets.Vault
+	Operator *Operator
 }
 
 func NewInterface(params Params) *Interface {
@@ -42,6 +51,8 @@ func NewInterface(params Params) *Interface {
 		config:   params.Config,
 		logger:   params.Logger.With(zap.String(logg.Layer, "Console")),
 		usecase:  params.Usecase,
+		secrets:  params.Secrets,
+		operator: params.Operator,
 		ctx:      ctx,
 		cancel:   cancel,
 		sigChan:  sigChan,
@@ -64,21 +75,22 @@ func (i *Interface) Start() error {
 		i.Stop()
 	}()
 
-	scanner := bufio.NewScanner(os.Stdin)
+	commands := i.operator.Commands()
 
 	for {
 		if i.stopping {
 			break
 		}
 
-		fmt.Print("\n> ")
+		if !i.taskRunning.Load() {
+			fmt.Print("\n> ")
+		}
 
-		if !scanner.Scan() {
+		input, ok := <-commands
+		if !ok {
 			break
 		}
 
-		input := strings.TrimSpace(scanner.Text())
-
 		if input == "" {
 			continue
 		}
@@ -118,6 +130,22 @@ func (i *Interface) Stop() error {
 }
 
 func (i *Interface) handleCommand(input string) error {
+	if i.taskRunning.Load() {
+		return i.handleTaskCommand(input)
+	}
+
+	if input == "schema" || strings.HasPrefix(input, "schema ") {
+		return i.setResultSchema(strings.TrimSpace(strings.TrimPrefix(input, "schema")))
+	}
+
+	if input == "dryrun" || strings.HasPrefix(input, "dryrun ") {
+		return i.setDryRun(strings.TrimSpace(strings.TrimPrefix(input, "dryrun")))
+	}
+
+	if input == "secret" || strings.HasPrefix(input, "secret ") {
+		return i.handleSecretCommand(strings.Fields(strings.TrimPrefix(input, "secret")))
+	}
+
 	switch input {
 	case "help", "h":
 		i.printHelp()
@@ -128,15 +156,140 @@ func (i *Interface) handleCommand(input string) error {
 
 		return fmt.Errorf("exit")
 	default:
-		return i.executeTask(input)
+		i.taskRunning.Store(true)
+
+		go func() {
+			defer i.taskRunning.Store(false)
+
+			if err := i.executeTask(input); err != nil {
+				i.logger.Error("Task error", zap.Error(err))
+			}
+
+			fmt.Print("\n> ")
+		}()
+
+		return nil
 	}
 }
 
+func (i *Interface) handleTaskCommand(input string) error {
+	command, arg, _ := strings.Cut(input, " ")
+
+	switch command {
+	case "pause":
+		i.usecase.Agent.Pause()
+		fmt.Println("⏸  Pausing after the current step...")
+	case "resume":
+		i.usecase.Agent.Resume(strings.TrimSpace(arg))
+	case "stop":
+		i.usecase.Agent.Stop()
+	case "help", "h":
+		fmt.Println("A task is running. Commands: pause, resume [note for the agent], stop")
+	default:
+		fmt.Println("A task is running. Use pause, resume [note] or stop")
+	}
+
+	return nil
+}
+
+func (i *Interface) setResultSchema(path string) error {
+	switch path {
+	case "":
+		if i.resultSchema == nil {
+			fmt.Println("No result schema set, results are free-form text")
+		} else {
+			encoded, _ := json.MarshalIndent(i.resultSchema, "", "  ")
+			fmt.Printf("Result schema:\n%s\n", encoded)
+		}
+
+		return nil
+	case "off", "clear":
+		i.resultSchema = nil
+		fmt.Println("Result schema cleared")
+
+		return nil
+	}
+
+	schema, err := jsonschema.Load(path)
+	if err != nil {
+		return err
+	}
+
+	i.resultSchema = schema
+	fmt.Printf("Result schema loaded from %s\n", path)
+
+	return nil
+}
+
+func (i *Interface) setDryRun(mode string) error {
+	switch mode {
+	case "on":
+		i.usecase.Agent.SetDryRun(true)
+		fmt.Println("Dry-run enabled: clicks, fills and key presses will only be simulated")
+	case "off":
+		i.usecase.Agent.SetDryRun(false)
+		fmt.Println("Dry-run disabled: actions will be executed")
+	default:
+		return fmt.Errorf("usage: dryrun on|off")
+	}
+
+	return nil
+}
+
+func (i *Interface) handleSecretCommand(args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		names := i.secrets.Names()
+		if len(names) == 0 {
+			fmt.Println("No secrets stored")
+
+			return nil
+		}
+
+		for _, name := range names {
+			fmt.Printf("  %s\n", secrets.Placeholder(name))
+		}
+
+		return nil
+	}
+
+	if len(args) < 2 {
+		return fmt.Errorf("usage: secret set|delete <name>")
+	}
+
+	switch args[0] {
+	case "set":
+		value, err := i.operator.AskSecret(i.ctx, fmt.Sprintf("Value for %s: ", args[1]))
+		if err != nil {
+			return err
+		}
+
+		if value == "" {
+			return fmt.Errorf("secret value cannot be empty")
+		}
+
+		if err := i.secrets.Set(args[1], value); err != nil {
+			return err
+		}
+
+		fmt.Printf("Saved, use %s in tasks\n", secrets.Placeholder(strings.ToLower(args[1])))
+	case "delete", "rm":
+		if err := i.secrets.Delete(args[1]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted %s\n", args[1])
+	default:
+		return fmt.Errorf("unknown secret command: %s", args[0])
+	}
+
+	return nil
+}
+
 func (i *Interface) executeTask(taskDescription string) error {
 	fmt.Printf("\n🤖 Starting task: %s\n", taskDescription)
 	fmt.Println("───────────────────────────────────────────────────")
 
-	task, err := i.usecase.Agent.Execute(i.ctx, taskDescription)
+	task, err := i.usecase.Agent.Execute(i.ctx, taskDescription, i.resultSchema)
 	if err != nil {
 		fmt.Printf("\n❌ Task failed: %v\n", err)
 
@@ -147,8 +300,21 @@ func (i *Interface) executeTask(taskDescription string) error {
 
 	if task.Status == "completed" {
 		fmt.Printf("✅ Task completed successfully!\n\n")
-		fmt.Printf("Result: %s\n", task.Result)
+
+		if task.StructuredResult != nil {
+			encoded, _ := json.MarshalIndent(task.StructuredResult, "", "  ")
+			fmt.Printf("Result:\n%s\n", encoded)
+		} else {
+			fmt.Printf("Result: %s\n", task.Result)
+		}
+
 		fmt.Printf("Steps taken: %d\n", len(task.Steps))
+
+		for _, download := range task.Downloads {
+			if download.Error == "" {
+				fmt.Printf("Downloaded: %s\n", download.Path)
+			}
+		}
 	} else {
 		fmt.Printf("❌ Task failed: %s\n", task.Error)
 	}
@@ -172,8 +338,19 @@ func (i *Interface) printBanner() {
 func (i *Interface) printHelp() {
 	help := `
 Available commands:
-  help, h       - Show this help message
-  exit, quit, q - Exit the application
+  help, h              - Show this help message
+  schema <file>        - Require results matching a JSON schema file
+  schema off           - Return to free-form text results
+  dryrun on|off        - Simulate mutating actions instead of executing them
+  secret               - List stored secret placeholders
+  secret set <name>    - Store a secret, referenced as {{secret:name}}
+  secret delete <name> - Remove a stored secret
+  exit, quit, q        - Exit the application
+
+While a task is running:
+  pause                - Pause after the current step and take over the browser
+  resume [note]        - Hand control back, optionally with a note for the agent
+  stop                 - Stop the task
 
 To start a task, simply type your request in natural language:
   Examples:
@@ -0,0 +1,121 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// Operator owns stdin. Lines typed while someone is waiting in Ask are
+// delivered to that caller, everything else is treated as a console command.
+type Operator struct {
+	once     sync.Once
+	mu       sync.Mutex
+	commands chan string
+	answer   chan string
+}
+
+func NewOperator() *Operator {
+	return &Operator{
+		commands: make(chan string),
+	}
+}
+
+func (o *Operator) start() {
+	o.once.Do(func() {
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+
+			for scanner.Scan() {
+				line := strings.TrimSuffix(scanner.Text(), "\r")
+
+				o.mu.Lock()
+				answer := o.answer
+				o.answer = nil
+				o.mu.Unlock()
+
+				if answer != nil {
+					answer <- line
+
+					continue
+				}
+
+				o.commands <- strings.TrimSpace(line)
+			}
+
+			close(o.commands)
+		}()
+	})
+}
+
+func (o *Operator) Commands() <-chan string {
+	o.start()
+
+	return o.commands
+}
+
+func (o *Operator) Ask(ctx context.Context, prompt string) (string, error) {
+	line, err := o.ask(ctx, prompt)
+
+	return strings.TrimSpace(line), err
+}
+
+// AskSecret reads a line with terminal echo turned off and returns it byte
+// for byte, without trimming.
+func (o *Operator) AskSecret(ctx context.Context, prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+
+	if term.IsTerminal(fd) {
+		if err := setEcho(fd, false); err != nil {
+			return "", fmt.Errorf("hide input: %w", err)
+		}
+
+		defer func() {
+			setEcho(fd, true)
+			fmt.Println()
+		}()
+	}
+
+	return o.ask(ctx, prompt)
+}
+
+func (o *Operator) ask(ctx context.Context, prompt string) (string, error) {
+	o.start()
+
+	answer := make(chan string, 1)
+
+	o.mu.Lock()
+	o.answer = answer
+	o.mu.Unlock()
+
+	fmt.Print(prompt)
+
+	select {
+	case line := <-answer:
+		return line, nil
+	case <-ctx.Done():
+		o.mu.Lock()
+		if o.answer == answer {
+			o.answer = nil
+		}
+		o.mu.Unlock()
+
+		return "", ctx.Err()
+	}
+}
+
+func (o *Operator) Confirm(ctx context.Context, prompt string) (bool, error) {
+	line, err := o.Ask(ctx, prompt)
+	if err != nil {
+		return false, err
+	}
+
+	line = strings.ToLower(line)
+
+	return line == "yes" || line == "y", nil
+}
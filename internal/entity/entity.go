@@ -7,14 +7,18 @@ import (
 )
 
 type Task struct {
-	ID          uuid.UUID
-	Description string
-	Status      TaskStatus
-	CreatedAt   time.Time
-	CompletedAt *time.Time
-	Steps       []Step
-	Result      string
-	Error       string
+	ID               uuid.UUID
+	Description      string
+	Status           TaskStatus
+	CreatedAt        time.Time
+	CompletedAt      *time.Time
+	Steps            []Step
+	Result           string
+	ResultSchema     map[string]interface{}
+	StructuredResult interface{}
+	DryRun           bool
+	Downloads        []Download
+	Error            string
 }
 
 type TaskStatus string
@@ -34,17 +38,30 @@ type Step struct {
 	Success     bool
 	Error       string
 	Screenshot  string
+	Simulated   bool
 }
 
 type BrowserAction struct {
-	Type       ActionType
-	Selector   string
-	Value      string
-	URL        string
-	WaitFor    int
-	X          float64
-	Y          float64
-	Screenshot bool
+	Type           ActionType
+	Selector       string
+	ElementID      int
+	Target         string
+	TargetID       int
+	Value          string
+	URL            string
+	WaitFor        int
+	X              float64
+	Y              float64
+	TabID          int
+	Accept         bool
+	Page           int
+	Limit          int
+	Exact          bool
+	ScrollIntoView bool
+	Delay          int
+	Clear          bool
+	SimulateTyping bool
+	Screenshot     bool
 }
 
 type ActionType string
@@ -61,18 +78,137 @@ const (
 	ActionTypeScroll           ActionType = "scroll"
 	ActionTypeHover            ActionType = "hover"
 	ActionTypePress            ActionType = "press"
+	ActionTypeAskUser          ActionType = "ask_user"
+	ActionTypeListTabs         ActionType = "list_tabs"
+	ActionTypeSwitchTab        ActionType = "switch_tab"
+	ActionTypeCloseTab         ActionType = "close_tab"
+	ActionTypeNewTab           ActionType = "new_tab"
+	ActionTypeHandleDialog     ActionType = "handle_dialog"
+	ActionTypeUploadFile       ActionType = "upload_file"
+	ActionTypeReadPage         ActionType = "read_page"
+	ActionTypeExtractData      ActionType = "extract_data"
+	ActionTypeFindText         ActionType = "find_text"
+	ActionTypeGoBack           ActionType = "go_back"
+	ActionTypeGoForward        ActionType = "go_forward"
+	ActionTypeReload           ActionType = "reload"
+	ActionTypeCheck            ActionType = "check"
+	ActionTypeUncheck          ActionType = "uncheck"
+	ActionTypeDrag             ActionType = "drag"
+	ActionTypeTypeText         ActionType = "type_text"
+	ActionTypeScrollUntil      ActionType = "scroll_until"
 )
 
+var ActionTypes = []ActionType{
+	ActionTypeNavigate,
+	ActionTypeClick,
+	ActionTypeClickCoordinates,
+	ActionTypeFill,
+	ActionTypeSelect,
+	ActionTypeWait,
+	ActionTypeScreenshot,
+	ActionTypeGetAttribute,
+	ActionTypeScroll,
+	ActionTypeHover,
+	ActionTypePress,
+	ActionTypeAskUser,
+	ActionTypeListTabs,
+	ActionTypeSwitchTab,
+	ActionTypeCloseTab,
+	ActionTypeNewTab,
+	ActionTypeHandleDialog,
+	ActionTypeUploadFile,
+	ActionTypeReadPage,
+	ActionTypeExtractData,
+	ActionTypeFindText,
+	ActionTypeGoBack,
+	ActionTypeGoForward,
+	ActionTypeReload,
+	ActionTypeCheck,
+	ActionTypeUncheck,
+	ActionTypeDrag,
+	ActionTypeTypeText,
+	ActionTypeScrollUntil,
+}
+
+type PageContent struct {
+	URL      string
+	Title    string
+	Scope    string
+	Markdown string
+	Page     int
+	Pages    int
+}
+
+type ExtractedData struct {
+	Source string
+	Fields []string
+	Rows   []map[string]string
+	Total  int
+}
+
+type TextMatch struct {
+	Element    Element
+	Context    string
+	InViewport bool
+}
+
+type TextSearch struct {
+	Query    string
+	Matches  []TextMatch
+	Total    int
+	Scrolled bool
+}
+
+type ScrollResult struct {
+	Container string
+	Moved     bool
+	AtTop     bool
+	AtBottom  bool
+	Progress  int
+}
+
+type ScrollCondition struct {
+	Text     string
+	Selector string
+	Count    int
+}
+
+type ScrollProgress struct {
+	Container string
+	Rounds    int
+	Items     int
+	AtBottom  bool
+	Met       bool
+	Stopped   string
+	Match     *Element
+}
+
+// TypingSegment is either text typed character by character or a single key
+// or chord such as Enter or Control+A.
+type TypingSegment struct {
+	Text string
+	Key  string
+}
+
+type TypingResult struct {
+	Value       string
+	Suggestions []Element
+}
+
 type PageState struct {
-	URL        string
-	Title      string
-	HTML       string
-	Screenshot string
-	Elements   []Element
-	Timestamp  time.Time
+	URL             string
+	Title           string
+	HTML            string
+	Screenshot      string
+	Elements        []Element
+	AXNodes         []AXNode
+	Dialogs         []Dialog
+	FileChooserOpen bool
+	Timestamp       time.Time
 }
 
 type Element struct {
+	ID          int
 	Tag         string
 	Text        string
 	Selector    string
@@ -83,6 +219,40 @@ type Element struct {
 	BoundingBox BoundingBox
 }
 
+type Download struct {
+	Name  string
+	Path  string
+	URL   string
+	Size  int64
+	Error string
+}
+
+type Dialog struct {
+	Type         string
+	Message      string
+	DefaultValue string
+	URL          string
+	TabID        int
+	Outcome      string
+}
+
+type Tab struct {
+	ID     int
+	URL    string
+	Title  string
+	Active bool
+}
+
+type AXNode struct {
+	Role        string
+	Name        string
+	Value       string
+	States      []string
+	Depth       int
+	Selector    string
+	BoundingBox BoundingBox
+}
+
 type BoundingBox struct {
 	X      float64
 	Y      float64
@@ -108,11 +278,12 @@ type AIMessage struct {
 }
 
 type AIResponse struct {
-	Action   *BrowserAction
-	Thought  string
-	NextStep string
-	Complete bool
-	Result   string
+	Action           *BrowserAction
+	Thought          string
+	NextStep         string
+	Complete         bool
+	Result           string
+	StructuredResult interface{}
 }
 
 type PageContext struct {
@@ -0,0 +1,103 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+var ErrUnsupportedFormat = errors.New("text extraction is not supported for this file type")
+
+// ExtractText returns up to maxChars characters of readable text from CSV,
+// TXT and PDF files.
+func ExtractText(path string, maxChars int) (text string, truncated bool, err error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt", ".csv", ".tsv", ".json", ".md":
+		text, err = readPlain(path, maxChars)
+	case ".pdf":
+		text, err = readPDF(path)
+	default:
+		return "", false, ErrUnsupportedFormat
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	text = strings.TrimSpace(text)
+
+	if maxChars > 0 && utf8.RuneCountInString(text) > maxChars {
+		return string([]rune(text)[:maxChars]), true, nil
+	}
+
+	return text, false, nil
+}
+
+func readPlain(path string, maxChars int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// UTF-8 runes take at most 4 bytes, read one extra so truncation is detected.
+	limit := int64(maxChars)*4 + 4
+	if maxChars <= 0 {
+		limit = 1 << 20
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f, limit))
+	if err != nil {
+		return "", err
+	}
+
+	if !utf8.Valid(data) {
+		data = []byte(strings.ToValidUTF8(string(data), ""))
+	}
+
+	return string(data), nil
+}
+
+func readPDF(path string) (text string, err error) {
+	// The PDF reader panics on some malformed files.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("read pdf: %v", r)
+		}
+	}()
+
+	f, reader, err := pdf.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open pdf: %w", err)
+	}
+	defer f.Close()
+
+	plain, err := reader.GetPlainText()
+	if err != nil {
+		return "", fmt.Errorf("read pdf: %w", err)
+	}
+
+	data, err := io.ReadAll(plain)
+	if err != nil {
+		return "", fmt.Errorf("read pdf: %w", err)
+	}
+
+	return string(data), nil
+}
+
+func FormatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%d KB", size>>10)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
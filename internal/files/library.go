@@ -0,0 +1,84 @@
+package files
+
+import (
+	"ai-agent-task/internal/config"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var ErrUnknownAlias = errors.New("unknown file alias")
+
+// Library exposes the files the user pre-approved for upload. The agent only
+// ever sees aliases (file names inside the upload directory), never paths.
+type Library struct {
+	dir string
+}
+
+func NewLibrary(cfg *config.Config) (*Library, error) {
+	dir := cfg.FilesConfig.UploadDir
+	if dir == "" {
+		return &Library{}, nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolve upload dir: %w", err)
+	}
+
+	return &Library{dir: abs}, nil
+}
+
+func (l *Library) Enabled() bool {
+	return l.dir != ""
+}
+
+// Aliases lists the approved files. The directory is read on every call so
+// files dropped in while the agent runs are picked up.
+func (l *Library) Aliases() []string {
+	if !l.Enabled() {
+		return nil
+	}
+
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil
+	}
+
+	aliases := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		if info, err := os.Stat(filepath.Join(l.dir, entry.Name())); err == nil && info.Mode().IsRegular() {
+			aliases = append(aliases, entry.Name())
+		}
+	}
+
+	sort.Strings(aliases)
+
+	return aliases
+}
+
+func (l *Library) Resolve(alias string) (string, error) {
+	alias = strings.TrimSpace(alias)
+
+	if !l.Enabled() || alias == "" || strings.HasPrefix(alias, ".") ||
+		strings.ContainsAny(alias, `/\`) || alias != filepath.Base(alias) {
+		return "", fmt.Errorf("%w: %q", ErrUnknownAlias, alias)
+	}
+
+	path := filepath.Join(l.dir, alias)
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%w: %q", ErrUnknownAlias, alias)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,63 @@
+package policy
+
+import "ai-agent-task/internal/entity"
+
+// DefaultPolicy is used when no policy file is configured. It mirrors the
+// keyword checks the agent shipped with before policies were configurable.
+func DefaultPolicy() *Policy {
+	typing := []string{string(entity.ActionTypeFill), string(entity.ActionTypeTypeText)}
+	clicks := []string{string(entity.ActionTypeClick), string(entity.ActionTypeClickCoordinates)}
+
+	return &Policy{
+		Default: OutcomeAllow,
+		Rules: []Rule{
+			{
+				Name:    "sensitive-input",
+				Actions: typing,
+				Attributes: map[string]string{
+					"field": `(^|[^a-z])(password|passwd|pin|cvv|cvc|csc|card(number|num|no)?|cc-[a-z-]+|cc|one-time-code|otp)([^a-z]|$)`,
+				},
+				Outcome: OutcomeConfirm,
+				Reason:  "filling a credential or payment field",
+			},
+			{
+				Name:    "password-input",
+				Actions: typing,
+				Attributes: map[string]string{
+					"type": `^password$`,
+				},
+				Outcome: OutcomeConfirm,
+				Reason:  "filling a password field",
+			},
+			{
+				Name:    "destructive-value",
+				Actions: typing,
+				Values:  []string{`delete|remove|удалить`},
+				Outcome: OutcomeConfirm,
+				Reason:  "typing a destructive command",
+			},
+			{
+				Name:    "destructive-click",
+				Actions: clicks,
+				Text:    []string{`delete|remove|удалить`},
+				Outcome: OutcomeConfirm,
+				Reason:  "clicking a destructive control",
+			},
+			{
+				Name:    "destructive-dialog",
+				Actions: []string{string(entity.ActionTypeHandleDialog)},
+				Text:    []string{`delete|remove|удалить|discard|cancel (order|subscription)|are you sure|вы уверены`},
+				Outcome: OutcomeConfirm,
+				Reason:  "accepting a destructive confirmation dialog",
+			},
+			{
+				Name:    "payment-click",
+				Actions: clicks,
+				Text:    []string{`\bpay|оплат|купить|\bbuy|checkout|оформить заказ|place order`},
+				URL:     []string{`payment|checkout|cart|basket|оплата|корзина`},
+				Outcome: OutcomeConfirm,
+				Reason:  "clicking a purchase or payment control",
+			},
+		},
+	}
+}
@@ -0,0 +1,141 @@
+package policy
+
+import (
+	"ai-agent-task/internal/config"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type URLFilter struct {
+	allowDomains  []string
+	denyDomains   []string
+	allowPatterns []*regexp.Regexp
+	denyPatterns  []*regexp.Regexp
+	blockRequests bool
+}
+
+func NewURLFilter(cfg *config.Config) (*URLFilter, error) {
+	nav := cfg.NavigationConfig
+
+	allowPatterns, err := compileAll(nav.AllowedURLPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("allowed url patterns: %w", err)
+	}
+
+	denyPatterns, err := compileAll(nav.DeniedURLPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("denied url patterns: %w", err)
+	}
+
+	return &URLFilter{
+		allowDomains:  normalizeDomains(nav.AllowedDomains),
+		denyDomains:   normalizeDomains(nav.DeniedDomains),
+		allowPatterns: allowPatterns,
+		denyPatterns:  denyPatterns,
+		blockRequests: nav.BlockRequests,
+	}, nil
+}
+
+func (f *URLFilter) Enabled() bool {
+	return len(f.allowDomains)+len(f.denyDomains)+len(f.allowPatterns)+len(f.denyPatterns) > 0
+}
+
+func (f *URLFilter) BlockRequests() bool {
+	return f.blockRequests && f.Enabled()
+}
+
+// Check decides whether the agent may open rawURL. Deny entries always win;
+// when any allow entry is configured the URL must match one of them.
+func (f *URLFilter) Check(rawURL string) (allowed bool, reason string) {
+	if !f.Enabled() {
+		return true, ""
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false, "malformed URL"
+	}
+
+	switch parsed.Scheme {
+	case "http", "https":
+	case "about", "data", "blob":
+		return true, ""
+	default:
+		return false, fmt.Sprintf("scheme %q is not allowed", parsed.Scheme)
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+
+	if d, ok := matchHost(f.denyDomains, host); ok {
+		return false, fmt.Sprintf("domain %s is denied", d)
+	}
+
+	if matchAny(f.denyPatterns, rawURL) {
+		return false, "URL matches a denied pattern"
+	}
+
+	if len(f.allowDomains) == 0 && len(f.allowPatterns) == 0 {
+		return true, ""
+	}
+
+	if _, ok := matchHost(f.allowDomains, host); ok {
+		return true, ""
+	}
+
+	if matchAny(f.allowPatterns, rawURL) {
+		return true, ""
+	}
+
+	return false, fmt.Sprintf("domain %s is not in the allowlist", host)
+}
+
+// CheckRequest is used for sub-resource requests. Unlike navigations they
+// are only blocked by deny entries, so pages on allowed domains can still
+// load assets from CDNs that are not explicitly allowlisted.
+func (f *URLFilter) CheckRequest(rawURL string, isNavigation bool) (allowed bool, reason string) {
+	if isNavigation {
+		return f.Check(rawURL)
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return true, ""
+	}
+
+	if d, ok := matchHost(f.denyDomains, strings.ToLower(parsed.Hostname())); ok {
+		return false, fmt.Sprintf("domain %s is denied", d)
+	}
+
+	if matchAny(f.denyPatterns, rawURL) {
+		return false, "URL matches a denied pattern"
+	}
+
+	return true, ""
+}
+
+func matchHost(domains []string, host string) (string, bool) {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return d, true
+		}
+	}
+
+	return "", false
+}
+
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		d = strings.TrimPrefix(d, "*.")
+
+		if d != "" {
+			normalized = append(normalized, d)
+		}
+	}
+
+	return normalized
+}
@@ -0,0 +1,83 @@
+package policy
+
+import (
+	"ai-agent-task/internal/config"
+	"testing"
+)
+
+func newTestFilter(t *testing.T, nav config.NavigationConfig) *URLFilter {
+	t.Helper()
+
+	filter, err := NewURLFilter(&config.Config{NavigationConfig: &nav})
+	if err != nil {
+		t.Fatalf("NewURLFilter() error = %v", err)
+	}
+
+	return filter
+}
+
+func TestURLFilterCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		nav     config.NavigationConfig
+		url     string
+		allowed bool
+	}{
+		{name: "no rules", url: "ftp://anything", allowed: true},
+		{name: "allowed domain", nav: config.NavigationConfig{AllowedDomains: []string{"example.com"}}, url: "https://example.com/a", allowed: true},
+		{name: "allowed subdomain", nav: config.NavigationConfig{AllowedDomains: []string{"*.example.com"}}, url: "https://shop.example.com", allowed: true},
+		{name: "lookalike domain", nav: config.NavigationConfig{AllowedDomains: []string{"example.com"}}, url: "https://badexample.com"},
+		{name: "allowlisted name as a subdomain", nav: config.NavigationConfig{AllowedDomains: []string{"example.com"}}, url: "https://example.com.evil.io"},
+		{name: "host is case insensitive", nav: config.NavigationConfig{AllowedDomains: []string{" Example.COM "}}, url: "https://WWW.EXAMPLE.com", allowed: true},
+		{name: "deny wins over allow", nav: config.NavigationConfig{AllowedDomains: []string{"example.com"}, DeniedDomains: []string{"admin.example.com"}}, url: "https://x.admin.example.com"},
+		{name: "deny only", nav: config.NavigationConfig{DeniedDomains: []string{"evil.io"}}, url: "https://good.io", allowed: true},
+		{name: "denied pattern", nav: config.NavigationConfig{DeniedURLPatterns: []string{`/logout`}}, url: "https://a.io/LOGOUT"},
+		{name: "allowed pattern", nav: config.NavigationConfig{AllowedURLPatterns: []string{`^https://docs\.`}}, url: "https://docs.a.io/x", allowed: true},
+		{name: "outside the allowlist", nav: config.NavigationConfig{AllowedURLPatterns: []string{`^https://docs\.`}}, url: "https://a.io/x"},
+		{name: "internal pages stay reachable", nav: config.NavigationConfig{AllowedDomains: []string{"example.com"}}, url: "about:blank", allowed: true},
+		{name: "other schemes are blocked", nav: config.NavigationConfig{DeniedDomains: []string{"evil.io"}}, url: "file:///etc/passwd"},
+		{name: "javascript urls are blocked", nav: config.NavigationConfig{DeniedDomains: []string{"evil.io"}}, url: "javascript:alert(1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, reason := newTestFilter(t, tt.nav).Check(tt.url)
+			if allowed != tt.allowed {
+				t.Fatalf("Check(%q) = %t (%s), want %t", tt.url, allowed, reason, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestURLFilterCheckRequest(t *testing.T) {
+	filter := newTestFilter(t, config.NavigationConfig{
+		AllowedDomains: []string{"example.com"},
+		DeniedDomains:  []string{"tracker.io"},
+	})
+
+	tests := []struct {
+		name         string
+		url          string
+		isNavigation bool
+		allowed      bool
+	}{
+		{name: "asset from a cdn", url: "https://cdn.other.net/app.js", allowed: true},
+		{name: "asset from a denied domain", url: "https://px.tracker.io/p.gif"},
+		{name: "navigation outside the allowlist", url: "https://cdn.other.net/", isNavigation: true},
+		{name: "navigation inside the allowlist", url: "https://example.com/", isNavigation: true, allowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allowed, _ := filter.CheckRequest(tt.url, tt.isNavigation); allowed != tt.allowed {
+				t.Fatalf("CheckRequest(%q, %t) = %t, want %t", tt.url, tt.isNavigation, allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestURLFilterInvalidPattern(t *testing.T) {
+	if _, err := NewURLFilter(&config.Config{NavigationConfig: &config.NavigationConfig{DeniedURLPatterns: []string{"("}}}); err == nil {
+		t.Fatal("NewURLFilter() error = nil, want pattern error")
+	}
+}
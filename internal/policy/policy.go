@@ -0,0 +1,304 @@
+package policy
+
+import (
+	"ai-agent-task/internal/config"
+	"ai-agent-task/internal/entity"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Outcome string
+
+const (
+	OutcomeAllow   Outcome = "allow"
+	OutcomeConfirm Outcome = "confirm"
+	OutcomeDeny    Outcome = "deny"
+)
+
+type Rule struct {
+	Name       string            `yaml:"name" json:"name"`
+	Actions    []string          `yaml:"actions" json:"actions"`
+	Text       []string          `yaml:"text" json:"text"`
+	Attributes map[string]string `yaml:"attributes" json:"attributes"`
+	URL        []string          `yaml:"url" json:"url"`
+	Domains    []string          `yaml:"domains" json:"domains"`
+	Values     []string          `yaml:"values" json:"values"`
+	Outcome    Outcome           `yaml:"outcome" json:"outcome"`
+	Reason     string            `yaml:"reason" json:"reason"`
+
+	text       []*regexp.Regexp
+	attributes map[string]*regexp.Regexp
+	url        []*regexp.Regexp
+	values     []*regexp.Regexp
+}
+
+type Policy struct {
+	Default Outcome `yaml:"default" json:"default"`
+	Rules   []Rule  `yaml:"rules" json:"rules"`
+}
+
+type Subject struct {
+	Action  entity.ActionType
+	URL     string
+	Value   string
+	Element *entity.Element
+}
+
+type Decision struct {
+	Outcome Outcome
+	Rule    string
+	Reason  string
+}
+
+type Engine struct {
+	policy *Policy
+}
+
+func NewEngine(cfg *config.Config) (*Engine, error) {
+	path := cfg.AgentConfig.PolicyFile
+	if path == "" {
+		return newEngine(DefaultPolicy())
+	}
+
+	p, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEngine(p)
+}
+
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy: %w", err)
+	}
+
+	var p Policy
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &p)
+	default:
+		err = yaml.Unmarshal(data, &p)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decode policy %s: %w", path, err)
+	}
+
+	return &p, nil
+}
+
+func newEngine(p *Policy) (*Engine, error) {
+	if p.Default == "" {
+		p.Default = OutcomeAllow
+	}
+
+	if !validOutcome(p.Default) {
+		return nil, fmt.Errorf("policy: invalid default outcome %q", p.Default)
+	}
+
+	for i := range p.Rules {
+		if err := p.Rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("policy rule %d (%s): %w", i, p.Rules[i].Name, err)
+		}
+	}
+
+	return &Engine{policy: p}, nil
+}
+
+func (e *Engine) Evaluate(subject Subject) Decision {
+	for i := range e.policy.Rules {
+		rule := &e.policy.Rules[i]
+
+		if rule.matches(subject) {
+			return Decision{
+				Outcome: rule.Outcome,
+				Rule:    rule.Name,
+				Reason:  rule.Reason,
+			}
+		}
+	}
+
+	return Decision{Outcome: e.policy.Default}
+}
+
+// toolActions maps tool names that differ from their action type, so a
+// policy written with the tool name gets a helpful error.
+var toolActions = map[string]entity.ActionType{
+	"click_at_coordinates": entity.ActionTypeClickCoordinates,
+	"select_option":        entity.ActionTypeSelect,
+}
+
+func (r *Rule) compile() error {
+	if !validOutcome(r.Outcome) {
+		return fmt.Errorf("invalid outcome %q", r.Outcome)
+	}
+
+	for _, action := range r.Actions {
+		if slices.Contains(entity.ActionTypes, entity.ActionType(action)) {
+			continue
+		}
+
+		if actual, ok := toolActions[action]; ok {
+			return fmt.Errorf("unknown action %q, use %q", action, actual)
+		}
+
+		return fmt.Errorf("unknown action %q", action)
+	}
+
+	var err error
+
+	if r.text, err = compileAll(r.Text); err != nil {
+		return err
+	}
+
+	if r.url, err = compileAll(r.URL); err != nil {
+		return err
+	}
+
+	if r.values, err = compileAll(r.Values); err != nil {
+		return err
+	}
+
+	r.attributes = make(map[string]*regexp.Regexp, len(r.Attributes))
+
+	for name, pattern := range r.Attributes {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return fmt.Errorf("attribute %s: %w", name, err)
+		}
+
+		r.attributes[name] = re
+	}
+
+	return nil
+}
+
+func (r *Rule) matches(subject Subject) bool {
+	if len(r.Actions) > 0 && !containsAction(r.Actions, subject.Action) {
+		return false
+	}
+
+	if len(r.Domains) > 0 && !matchDomain(r.Domains, subject.URL) {
+		return false
+	}
+
+	if len(r.url) > 0 && !matchAny(r.url, subject.URL) {
+		return false
+	}
+
+	if len(r.values) > 0 && !matchAny(r.values, subject.Value) {
+		return false
+	}
+
+	if len(r.text) > 0 {
+		if subject.Element == nil || !matchAny(r.text, subject.Element.Text) {
+			return false
+		}
+	}
+
+	for name, re := range r.attributes {
+		if subject.Element == nil || !re.MatchString(elementAttribute(subject.Element, name)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func elementAttribute(elem *entity.Element, name string) string {
+	switch name {
+	case "tag":
+		return elem.Tag
+	case "selector":
+		return elem.Selector
+	case "field":
+		// The attributes that name a form field, without class or href
+		// noise such as "spinner" or "product-card".
+		values := make([]string, 0, 4)
+		for _, attr := range []string{"name", "id", "autocomplete", "type"} {
+			if v := elem.Attributes[attr]; v != "" {
+				values = append(values, v)
+			}
+		}
+
+		return strings.Join(values, " ")
+	case "any":
+		values := []string{elem.Selector}
+		for _, v := range elem.Attributes {
+			values = append(values, v)
+		}
+
+		return strings.Join(values, " ")
+	default:
+		return elem.Attributes[name]
+	}
+}
+
+func containsAction(actions []string, action entity.ActionType) bool {
+	for _, a := range actions {
+		if a == string(action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchDomain(domains []string, rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(d, "*."))
+
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchAny(patterns []*regexp.Regexp, value string) bool {
+	for _, re := range patterns {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compileAll(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, p := range patterns {
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", p, err)
+		}
+
+		compiled = append(compiled, re)
+	}
+
+	return compiled, nil
+}
+
+func validOutcome(o Outcome) bool {
+	return o == OutcomeAllow || o == OutcomeConfirm || o == OutcomeDeny
+}
@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"ai-agent-task/internal/entity"
+	"strings"
+	"testing"
+)
+
+func TestEngineEvaluate(t *testing.T) {
+	engine, err := newEngine(&Policy{
+		Default: OutcomeAllow,
+		Rules: []Rule{
+			{Name: "confirm-delete", Actions: []string{"click"}, Text: []string{`delete`}, Outcome: OutcomeConfirm},
+			{Name: "deny-admin", Actions: []string{"click", "fill"}, Domains: []string{"*.admin.example.com"}, Outcome: OutcomeDeny},
+			{Name: "deny-submit", Attributes: map[string]string{"type": `^submit$`, "tag": `^button$`}, Outcome: OutcomeDeny},
+			{Name: "confirm-checkout", URL: []string{`/checkout`}, Outcome: OutcomeConfirm},
+		},
+	})
+	if err != nil {
+		t.Fatalf("newEngine() error = %v", err)
+	}
+
+	button := func(text string, attrs map[string]string) *entity.Element {
+		return &entity.Element{Tag: "button", Text: text, Attributes: attrs}
+	}
+
+	tests := []struct {
+		name    string
+		subject Subject
+		want    Outcome
+		rule    string
+	}{
+		{name: "no rule matches", subject: Subject{Action: entity.ActionTypeClick, URL: "https://example.com", Element: button("Save", nil)}, want: OutcomeAllow},
+		{name: "text match", subject: Subject{Action: entity.ActionTypeClick, Element: button("Delete account", nil)}, want: OutcomeConfirm, rule: "confirm-delete"},
+		{name: "first matching rule wins", subject: Subject{Action: entity.ActionTypeClick, URL: "https://eu.admin.example.com", Element: button("Delete", nil)}, want: OutcomeConfirm, rule: "confirm-delete"},
+		{name: "action must match", subject: Subject{Action: entity.ActionTypeHover, Element: button("Delete", nil)}, want: OutcomeAllow},
+		{name: "text needs an element", subject: Subject{Action: entity.ActionTypeClick}, want: OutcomeAllow},
+		{name: "wildcard domain covers the apex", subject: Subject{Action: entity.ActionTypeFill, URL: "https://admin.example.com/users"}, want: OutcomeDeny, rule: "deny-admin"},
+		{name: "domain suffix needs a dot", subject: Subject{Action: entity.ActionTypeFill, URL: "https://evil-admin.example.com.io"}, want: OutcomeAllow},
+		{name: "all attributes must match", subject: Subject{Action: entity.ActionTypeClick, Element: button("Send", map[string]string{"type": "submit"})}, want: OutcomeDeny, rule: "deny-submit"},
+		{name: "one attribute is not enough", subject: Subject{Action: entity.ActionTypeClick, Element: &entity.Element{Tag: "input", Attributes: map[string]string{"type": "submit"}}}, want: OutcomeAllow},
+		{name: "rule without actions covers every action", subject: Subject{Action: entity.ActionTypeNavigate, URL: "https://shop.io/CHECKOUT"}, want: OutcomeConfirm, rule: "confirm-checkout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := engine.Evaluate(tt.subject)
+			if got.Outcome != tt.want || got.Rule != tt.rule {
+				t.Fatalf("Evaluate() = %s (%q), want %s (%q)", got.Outcome, got.Rule, tt.want, tt.rule)
+			}
+		})
+	}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	engine, err := newEngine(DefaultPolicy())
+	if err != nil {
+		t.Fatalf("newEngine() error = %v", err)
+	}
+
+	field := func(attrs map[string]string) *entity.Element {
+		return &entity.Element{Tag: "input", Attributes: attrs}
+	}
+
+	tests := []struct {
+		name    string
+		subject Subject
+		rule    string
+	}{
+		{name: "password name", subject: Subject{Action: entity.ActionTypeFill, Element: field(map[string]string{"name": "user_password"})}, rule: "sensitive-input"},
+		{name: "password type", subject: Subject{Action: entity.ActionTypeTypeText, Element: field(map[string]string{"type": "password"})}, rule: "sensitive-input"},
+		{name: "card autocomplete", subject: Subject{Action: entity.ActionTypeFill, Element: field(map[string]string{"autocomplete": "cc-number"})}, rule: "sensitive-input"},
+		{name: "camel case card id", subject: Subject{Action: entity.ActionTypeFill, Element: field(map[string]string{"id": "cardNumber"})}, rule: "sensitive-input"},
+		{name: "shipping is not pin", subject: Subject{Action: entity.ActionTypeFill, Element: field(map[string]string{"name": "shipping"})}},
+		{name: "card in class is ignored", subject: Subject{Action: entity.ActionTypeFill, Element: field(map[string]string{"class": "product-card", "name": "q"})}},
+		{name: "sensitive field on click", subject: Subject{Action: entity.ActionTypeClick, Element: field(map[string]string{"name": "password"})}},
+		{name: "destructive value", subject: Subject{Action: entity.ActionTypeFill, Value: "DELETE all", Element: field(nil)}, rule: "destructive-value"},
+		{name: "destructive click", subject: Subject{Action: entity.ActionTypeClickCoordinates, Element: &entity.Element{Text: "Remove item"}}, rule: "destructive-click"},
+		{name: "pay on checkout", subject: Subject{Action: entity.ActionTypeClick, URL: "https://shop.io/checkout", Element: &entity.Element{Text: "Pay now"}}, rule: "payment-click"},
+		{name: "pay outside checkout", subject: Subject{Action: entity.ActionTypeClick, URL: "https://shop.io/help", Element: &entity.Element{Text: "Pay now"}}},
+		{name: "destructive dialog", subject: Subject{Action: entity.ActionTypeHandleDialog, Element: &entity.Element{Text: "Are you sure?"}}, rule: "destructive-dialog"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := OutcomeAllow
+			if tt.rule != "" {
+				want = OutcomeConfirm
+			}
+
+			got := engine.Evaluate(tt.subject)
+			if got.Outcome != want || got.Rule != tt.rule {
+				t.Fatalf("Evaluate() = %s (%q), want %s (%q)", got.Outcome, got.Rule, want, tt.rule)
+			}
+		})
+	}
+}
+
+func TestNewEngineErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{name: "invalid default", policy: Policy{Default: "maybe"}, wantErr: `invalid default outcome "maybe"`},
+		{name: "invalid outcome", policy: Policy{Rules: []Rule{{Name: "r", Outcome: "block"}}}, wantErr: `invalid outcome "block"`},
+		{name: "unknown action", policy: Policy{Rules: []Rule{{Name: "r", Actions: []string{"tap"}, Outcome: OutcomeDeny}}}, wantErr: `unknown action "tap"`},
+		{name: "tool name", policy: Policy{Rules: []Rule{{Name: "r", Actions: []string{"select_option"}, Outcome: OutcomeDeny}}}, wantErr: `use "select"`},
+		{name: "bad text pattern", policy: Policy{Rules: []Rule{{Name: "r", Text: []string{"("}, Outcome: OutcomeDeny}}}, wantErr: "pattern"},
+		{name: "bad attribute pattern", policy: Policy{Rules: []Rule{{Name: "r", Attributes: map[string]string{"id": "["}, Outcome: OutcomeDeny}}}, wantErr: "attribute id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newEngine(&tt.policy)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("newEngine() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package secrets
+
+import (
+	"ai-agent-task/internal/config"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	minMaskLength = 4
+	saltLength    = 16
+	keyLength     = 32
+
+	kdfScrypt = "scrypt"
+
+	// scrypt cost parameters recommended for interactive logins.
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{\{\s*secret:([A-Za-z0-9_.-]+)\s*\}\}`)
+	namePattern        = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+	ErrNotWritable = errors.New("secrets file or key is not configured")
+)
+
+type Vault struct {
+	mu         sync.RWMutex
+	path       string
+	passphrase []byte
+	salt       []byte
+	key        []byte
+	values     map[string]string
+	fromEnv    map[string]bool
+}
+
+// vaultFile is the on-disk format: the key is derived from SECRETS_KEY with
+// scrypt and the salt stored next to the AES-GCM sealed values.
+type vaultFile struct {
+	KDF  string `json:"kdf"`
+	Salt []byte `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Data []byte `json:"data"`
+}
+
+func NewVault(cfg *config.Config) (*Vault, error) {
+	sc := cfg.SecretsConfig
+
+	v := &Vault{
+		path:    sc.File,
+		values:  make(map[string]string),
+		fromEnv: make(map[string]bool),
+	}
+
+	if sc.Key != "" {
+		v.passphrase = []byte(sc.Key)
+	}
+
+	if sc.File != "" {
+		if err := v.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	if sc.EnvPrefix != "" {
+		for _, kv := range os.Environ() {
+			name, value, ok := strings.Cut(kv, "=")
+			if !ok || !strings.HasPrefix(name, sc.EnvPrefix) || value == "" {
+				continue
+			}
+
+			secretName := strings.ToLower(strings.TrimPrefix(name, sc.EnvPrefix))
+			v.values[secretName] = value
+			v.fromEnv[secretName] = true
+		}
+	}
+
+	return v, nil
+}
+
+func Placeholder(name string) string {
+	return "{{secret:" + name + "}}"
+}
+
+func (v *Vault) Names() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	names := make([]string, 0, len(v.values))
+	for name := range v.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (v *Vault) HasPlaceholder(text string) bool {
+	return placeholderPattern.MatchString(text)
+}
+
+// Resolve replaces every {{secret:name}} placeholder with the stored value.
+// The result must only be handed to the browser and never logged.
+func (v *Vault) Resolve(text string) (string, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	var missing []string
+
+	resolved := placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := strings.ToLower(placeholderPattern.FindStringSubmatch(match)[1])
+
+		value, ok := v.values[name]
+		if !ok {
+			missing = append(missing, name)
+
+			return match
+		}
+
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("unknown secret(s): %s", strings.Join(missing, ", "))
+	}
+
+	return resolved, nil
+}
+
+// Mask replaces any stored secret value found in text with its placeholder.
+func (v *Vault) Mask(text string) string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for name, value := range v.values {
+		if len(value) < minMaskLength {
+			continue
+		}
+
+		text = strings.ReplaceAll(text, value, Placeholder(name))
+	}
+
+	return text
+}
+
+func (v *Vault) Set(name, value string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q", name)
+	}
+
+	if v.path == "" || v.passphrase == nil {
+		return ErrNotWritable
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.values[name] = value
+	delete(v.fromEnv, name)
+
+	return v.save()
+}
+
+func (v *Vault) Delete(name string) error {
+	if v.path == "" || v.passphrase == nil {
+		return ErrNotWritable
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.values, strings.ToLower(name))
+
+	return v.save()
+}
+
+func (v *Vault) load() error {
+	data, err := os.ReadFile(v.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("read secrets file: %w", err)
+	}
+
+	if v.passphrase == nil {
+		return fmt.Errorf("secrets file %s exists but SECRETS_KEY is not set", v.path)
+	}
+
+	var file vaultFile
+
+	if err := json.Unmarshal(data, &file); err != nil || file.KDF == "" {
+		return fmt.Errorf("secrets file %s is not a scrypt-sealed vault", v.path)
+	}
+
+	if file.KDF != kdfScrypt {
+		return fmt.Errorf("secrets file uses unsupported key derivation %q", file.KDF)
+	}
+
+	v.salt = file.Salt
+	v.key, err = scrypt.Key(v.passphrase, file.Salt, file.N, file.R, file.P, keyLength)
+	if err != nil {
+		return fmt.Errorf("derive secrets key: %w", err)
+	}
+
+	sealed := file.Data
+
+	gcm, err := v.cipher()
+	if err != nil {
+		return err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return errors.New("secrets file is corrupted")
+	}
+
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return errors.New("decrypt secrets file: wrong key or corrupted file")
+	}
+
+	var stored map[string]string
+
+	if err := json.Unmarshal(plain, &stored); err != nil {
+		return fmt.Errorf("decode secrets: %w", err)
+	}
+
+	for name, value := range stored {
+		v.values[strings.ToLower(name)] = value
+	}
+
+	// Files sealed with other cost parameters are re-sealed with the
+	// current ones.
+	if file.N != scryptN || file.R != scryptR || file.P != scryptP {
+		v.key = nil
+
+		if err := v.save(); err != nil {
+			return fmt.Errorf("re-seal secrets file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (v *Vault) save() error {
+	stored := make(map[string]string, len(v.values))
+
+	for name, value := range v.values {
+		if !v.fromEnv[name] {
+			stored[name] = value
+		}
+	}
+
+	plain, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("encode secrets: %w", err)
+	}
+
+	if v.key == nil {
+		v.salt = make([]byte, saltLength)
+		if _, err := rand.Read(v.salt); err != nil {
+			return fmt.Errorf("generate salt: %w", err)
+		}
+
+		v.key, err = scrypt.Key(v.passphrase, v.salt, scryptN, scryptR, scryptP, keyLength)
+		if err != nil {
+			return fmt.Errorf("derive secrets key: %w", err)
+		}
+	}
+
+	gcm, err := v.cipher()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generate nonce: %w", err)
+	}
+
+	data, err := json.Marshal(vaultFile{
+		KDF:  kdfScrypt,
+		Salt: v.salt,
+		N:    scryptN,
+		R:    scryptR,
+		P:    scryptP,
+		Data: gcm.Seal(nonce, nonce, plain, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("encode secrets file: %w", err)
+	}
+
+	if err := os.WriteFile(v.path, data, 0600); err != nil {
+		return fmt.Errorf("write secrets file: %w", err)
+	}
+
+	return nil
+}
+
+func (v *Vault) cipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(v.key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -9,25 +9,54 @@ type BrowserService interface {
 	Launch(ctx context.Context) error
 	Close(ctx context.Context) error
 	Navigate(ctx context.Context, url string) error
+	GoBack(ctx context.Context) error
+	GoForward(ctx context.Context) error
+	Reload(ctx context.Context) error
 	Click(ctx context.Context, selector string) error
 	ClickAtCoordinates(ctx context.Context, x, y float64) error
 	Fill(ctx context.Context, selector, value string) error
-	Scroll(ctx context.Context, direction string, amount int) error
+	Scroll(ctx context.Context, selector, direction string, amount int) (*entity.ScrollResult, error)
+	ScrollUntil(ctx context.Context, selector string, until entity.ScrollCondition, maxRounds int) (*entity.ScrollProgress, error)
 	WaitForSelector(ctx context.Context, selector string, timeout int) error
 	GetElementText(ctx context.Context, selector string) (string, error)
 	Screenshot(ctx context.Context, path string) error
 	GetPageState(ctx context.Context) (*entity.PageState, error)
 	GetElements(ctx context.Context) ([]entity.Element, error)
+	DescribeElement(ctx context.Context, selector string) (*entity.Element, error)
+	ElementAt(ctx context.Context, x, y float64) (*entity.Element, error)
 	EvaluateJS(ctx context.Context, script string) (interface{}, error)
+	Highlight(ctx context.Context, box entity.BoundingBox, label string) error
+	ListTabs(ctx context.Context) ([]entity.Tab, error)
+	SwitchTab(ctx context.Context, id int) error
+	CloseTab(ctx context.Context, id int) error
+	NewTab(ctx context.Context, url string) (int, error)
+	DrainNewTabs() []entity.Tab
+	PendingDialog() *entity.Dialog
+	AckDialogs(dialogs []entity.Dialog)
+	HandleDialog(ctx context.Context, accept bool, promptText string) (*entity.Dialog, error)
+	UploadFile(ctx context.Context, selector string, paths []string) error
+	ReadPage(ctx context.Context, selector string, page int) (*entity.PageContent, error)
+	ExtractData(ctx context.Context, selector string, limit int) (*entity.ExtractedData, error)
+	FindText(ctx context.Context, query string, exact, scroll bool) (*entity.TextSearch, error)
+	SelectOption(ctx context.Context, selector, option string) (string, error)
+	SetChecked(ctx context.Context, selector string, checked bool) error
+	Hover(ctx context.Context, selector string) error
+	Drag(ctx context.Context, from, to string, x, y float64) error
+	TypeText(ctx context.Context, selector string, segments []entity.TypingSegment, delay int, clear bool) (*entity.TypingResult, error)
+	SetDownloadDir(dir string)
+	DrainDownloads() []entity.Download
 	IsReady() bool
 }
 
 type AIService interface {
-	SendMessage(ctx context.Context, messages []entity.AIMessage) (*entity.AIResponse, error)
+	SendMessage(ctx context.Context, messages []entity.AIMessage, resultSchema map[string]interface{}) (*entity.AIResponse, error)
 	CreateTools() []interface{}
 }
 
 type AgentService interface {
-	Execute(ctx context.Context, taskDescription string) (*entity.Task, error)
+	Execute(ctx context.Context, taskDescription string, resultSchema map[string]interface{}) (*entity.Task, error)
 	Stop()
+	Pause()
+	Resume(note string)
+	SetDryRun(enabled bool)
 }
@@ -3,16 +3,23 @@ package usecase
 import (
 	"ai-agent-task/internal/config"
 	"ai-agent-task/internal/entity"
+	"ai-agent-task/internal/files"
+	"ai-agent-task/internal/policy"
 	"ai-agent-task/internal/ports"
+	"ai-agent-task/internal/secrets"
 	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/jsonschema"
 	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/redact"
 	"ai-agent-task/pkg/tracing"
-	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,47 +35,78 @@ const (
 	agentTracer      = "usecase.agent"
 	maxIterations    = 16
 	maxConsecutiveErrors = 3
+
+	pageStateAccessibility = "accessibility"
 )
 
 type AgentService struct {
-	config        *config.Config
-	logger        *zap.Logger
-	browser       ports.BrowserManager
-	ai            ports.AIClient
-	tracer        trace.Tracer
-	stopChan      chan struct{}
-	running       bool
-	lastURL       string
-	lastAction    *entity.BrowserAction
+	config         *config.Config
+	logger         *zap.Logger
+	browser        ports.BrowserManager
+	ai             ports.AIClient
+	operator       ports.Operator
+	policy         *policy.Engine
+	urlFilter      *policy.URLFilter
+	secrets        *secrets.Vault
+	files          *files.Library
+	redactor       *redact.Redactor
+	tracer         trace.Tracer
+	stopMu         sync.Mutex
+	stopChan       chan struct{}
+	running        atomic.Bool
+	lastURL        string
+	lastState      *entity.PageState
+	loops          *loopDetector
+	dryRun         bool
+	pauseRequested atomic.Bool
+	resumeChan     chan string
 }
 
 type AgentServiceParams struct {
 	fx.In
 
-	Config  *config.Config
-	Logger  *zap.Logger
-	Browser ports.BrowserManager
-	AI      ports.AIClient
+	Config    *config.Config
+	Logger    *zap.Logger
+	Browser   ports.BrowserManager
+	AI        ports.AIClient
+	Operator  ports.Operator
+	Policy    *policy.Engine
+	URLFilter *policy.URLFilter
+	Secrets   *secrets.Vault
+	Files     *files.Library
+	Redactor  *redact.Redactor
 }
 
 func NewAgentService(params AgentServiceParams) *AgentService {
 	return &AgentService{
-		config:   params.Config,
-		logger:   params.Logger.With(zap.String(logg.Layer, agentServiceName)),
-		browser:  params.Browser,
-		ai:       params.AI,
-		tracer:   otel.Tracer(agentTracer),
-		stopChan: make(chan struct{}),
-		running:  false,
+		config:     params.Config,
+		logger:     params.Logger.With(zap.String(logg.Layer, agentServiceName)),
+		browser:    params.Browser,
+		ai:         params.AI,
+		operator:   params.Operator,
+		policy:     params.Policy,
+		urlFilter:  params.URLFilter,
+		secrets:    params.Secrets,
+		files:      params.Files,
+		redactor:   params.Redactor,
+		tracer:     otel.Tracer(agentTracer),
+		stopChan:   make(chan struct{}),
+		loops:      newLoopDetector(params.Config.AgentConfig.LoopWindow),
+		dryRun:     params.Config.AgentConfig.DryRun,
+		resumeChan: make(chan string, 1),
 	}
 }
 
-func (s *AgentService) Execute(ctx context.Context, taskDescription string) (resp *entity.Task, err error) {
+func (s *AgentService) Execute(ctx context.Context, taskDescription string, resultSchema map[string]interface{}) (resp *entity.Task, err error) {
 	const op = "Execute"
 	logger := s.logger.With(zap.String(logg.Operation, op))
 
+	taskDescription = s.secrets.Mask(taskDescription)
+
 	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
-		attribute.String("task_description", taskDescription))
+		attribute.String("task_description", taskDescription),
+		attribute.Bool("structured_result", resultSchema != nil),
+		attribute.Bool("dry_run", s.dryRun))
 	defer func() {
 		step.End(err)
 	}()
@@ -78,11 +116,13 @@ func (s *AgentService) Execute(ctx context.Context, taskDescription string) (res
 	}
 
 	task := &entity.Task{
-		ID:          uuid.New(),
-		Description: taskDescription,
-		Status:      entity.TaskStatusInProgress,
-		CreatedAt:   time.Now(),
-		Steps:       make([]entity.Step, 0),
+		ID:           uuid.New(),
+		Description:  taskDescription,
+		Status:       entity.TaskStatusInProgress,
+		CreatedAt:    time.Now(),
+		Steps:        make([]entity.Step, 0),
+		ResultSchema: resultSchema,
+		DryRun:       s.dryRun,
 	}
 
 	logger = logger.With(zap.String(logg.TaskID, task.ID.String()))
@@ -95,7 +135,7 @@ func (s *AgentService) Execute(ctx context.Context, taskDescription string) (res
 		return task, apperr.WrapErrorWithReason(op, apperr.CodeBrowserNotReady, "browser_not_ready")
 	}
 
-	systemPrompt := s.buildSystemPrompt(taskDescription)
+	systemPrompt := s.buildSystemPrompt(taskDescription, resultSchema)
 
 	messages := []entity.AIMessage{
 		{
@@ -104,12 +144,27 @@ func (s *AgentService) Execute(ctx context.Context, taskDescription string) (res
 		},
 	}
 
-	s.running = true
-	s.stopChan = make(chan struct{})
+	stop := s.start()
+	defer s.running.Store(false)
+
+	s.pauseRequested.Store(false)
+
+	// A resume typed after the previous task ended must not answer this
+	// task's first pause.
+	select {
+	case <-s.resumeChan:
+	default:
+	}
+
+	s.loops = newLoopDetector(s.config.AgentConfig.LoopWindow)
+	s.lastState = nil
+	s.browser.DrainNewTabs()
+	s.browser.DrainDownloads()
+	s.browser.SetDownloadDir(filepath.Join(s.config.FilesConfig.DownloadDir, task.ID.String()))
 	iteration := 0
 	consecutiveErrors := 0
 
-	for s.running && iteration < maxIterations {
+	for s.running.Load() && iteration < maxIterations {
 		// Check for cancellation before each iteration
 		select {
 		case <-ctx.Done():
@@ -120,7 +175,7 @@ func (s *AgentService) Execute(ctx context.Context, taskDescription string) (res
 			return task, apperr.Wrap(op, apperr.CodeInternal, ctx.Err(), map[string]any{
 				apperr.MetaReason: "context_cancelled",
 			})
-		case <-s.stopChan:
+		case <-stop:
 			fmt.Println("\n\n⚠️  Task stopped by user")
 			task.Status = entity.TaskStatusFailed
 			task.Error = "stopped by user"
@@ -130,7 +185,7 @@ func (s *AgentService) Execute(ctx context.Context, taskDescription string) (res
 			// Continue with iteration
 		}
 
-		if !s.running {
+		if !s.running.Load() {
 			fmt.Println("\n\n⚠️  Task stopped by user")
 			task.Status = entity.TaskStatusFailed
 			task.Error = "stopped by user"
@@ -138,12 +193,24 @@ func (s *AgentService) Execute(ctx context.Context, taskDescription string) (res
 			return task, apperr.WrapErrorWithReason(op, apperr.CodeCancelledByUser, "stopped_by_user")
 		}
 
+		if s.pauseRequested.Load() {
+			msg, err := s.waitForResume(ctx)
+			if err != nil {
+				task.Status = entity.TaskStatusFailed
+				task.Error = "stopped while paused"
+
+				return task, err
+			}
+
+			messages = append(messages, msg)
+		}
+
 		iteration++
 		fmt.Printf("\n🔄 Iteration %d: ", iteration)
 
 		step.AddEvent("sending message to AI")
 
-		response, err := s.ai.SendMessage(ctx, messages)
+		response, err := s.ai.SendMessage(ctx, messages, task.ResultSchema)
 		if err != nil {
 			logger.Error("AI request failed", zap.Error(err))
 			consecutiveErrors++
@@ -175,9 +242,32 @@ func (s *AgentService) Execute(ctx context.Context, taskDescription string) (res
 		}
 
 		if response.Complete {
+			if err := s.validateResult(task, response); err != nil {
+				logger.Warn("Result does not match schema", zap.Error(err))
+				consecutiveErrors++
+
+				if consecutiveErrors >= maxConsecutiveErrors {
+					task.Status = entity.TaskStatusFailed
+					task.Error = fmt.Sprintf("result does not match schema: %v", err)
+
+					return task, apperr.Wrap(op, apperr.CodeInvalidResult, err, map[string]any{
+						apperr.MetaReason: "result_schema_mismatch",
+						apperr.MetaStage:  apperr.StageExecution,
+					})
+				}
+
+				messages = append(messages, entity.AIMessage{
+					Role:    "user",
+					Content: fmt.Sprintf("The complete_task result does not match the required JSON schema: %v. Fix the result and call complete_task again.", err),
+				})
+
+				continue
+			}
+
 			fmt.Printf("✅ Task completed: %s\n", response.Result)
 			task.Status = entity.TaskStatusCompleted
 			task.Result = response.Result
+			task.StructuredResult = response.StructuredResult
 			completedAt := time.Now()
 			task.CompletedAt = &completedAt
 			step.AddEvent("task completed")
@@ -190,6 +280,16 @@ func (s *AgentService) Execute(ctx context.Context, taskDescription string) (res
 				logger.Error("Action failed", zap.Error(err))
 				consecutiveErrors++
 
+				if s.loops.stuck >= s.config.AgentConfig.StuckLimit {
+					task.Status = entity.TaskStatusFailed
+					task.Error = fmt.Sprintf("agent is stuck in a loop: %v", err)
+
+					return task, apperr.Wrap(op, apperr.CodeStuck, err, map[string]any{
+						apperr.MetaReason: "stuck_limit_reached",
+						apperr.MetaStage:  apperr.StageExecution,
+					})
+				}
+
 				if consecutiveErrors >= maxConsecutiveErrors {
 					task.Status = entity.TaskStatusFailed
 					task.Error = fmt.Sprintf("too many consecutive action errors: %v", err)
@@ -217,13 +317,63 @@ func (s *AgentService) Execute(ctx context.Context, taskDescription string) (res
 	return task, nil
 }
 
+func (s *AgentService) validateResult(task *entity.Task, response *entity.AIResponse) error {
+	if task.ResultSchema == nil {
+		return nil
+	}
+
+	if response.StructuredResult == nil {
+		return errors.New("structured result is missing")
+	}
+
+	return jsonschema.Validate(task.ResultSchema, response.StructuredResult)
+}
+
 func (s *AgentService) Stop() {
 	const op = "Stop"
 	logger := s.logger.With(zap.String(logg.Operation, op))
 	logger.Info("Stopping agent...")
 
-	s.running = false
-	close(s.stopChan)
+	s.stopMu.Lock()
+	defer s.stopMu.Unlock()
+
+	s.running.Store(false)
+
+	select {
+	case <-s.stopChan:
+	default:
+		close(s.stopChan)
+	}
+}
+
+// start marks a task as running with a fresh stop channel. The channel is
+// only swapped and closed under stopMu, so Stop from another goroutine never
+// closes a channel that is being replaced.
+func (s *AgentService) start() <-chan struct{} {
+	s.stopMu.Lock()
+	defer s.stopMu.Unlock()
+
+	s.stopChan = make(chan struct{})
+	s.running.Store(true)
+
+	return s.stopChan
+}
+
+func (s *AgentService) stopSignal() <-chan struct{} {
+	s.stopMu.Lock()
+	defer s.stopMu.Unlock()
+
+	return s.stopChan
+}
+
+func useElementRef(action *entity.BrowserAction) {
+	if action.ElementID > 0 && action.Selector == "" {
+		action.Selector = fmt.Sprintf("ref=%d", action.ElementID)
+	}
+
+	if action.TargetID > 0 && action.Target == "" {
+		action.Target = fmt.Sprintf("ref=%d", action.TargetID)
+	}
 }
 
 func (s *AgentService) handleAction(
@@ -241,36 +391,81 @@ func (s *AgentService) handleAction(
 		step.End(err)
 	}()
 
+	useElementRef(action)
+
 	taskStep := entity.Step{
 		ID:          uuid.New(),
 		Action:      string(action.Type),
-		Description: s.formatActionDescription(action),
+		Description: s.redactor.String(s.formatActionDescription(action)),
 		Timestamp:   time.Now(),
+		Simulated:   s.isSimulated(action),
 	}
 
-	fmt.Printf("🎬 Action: %s - %s\n", action.Type, taskStep.Description)
+	if taskStep.Simulated {
+		fmt.Printf("🧪 Simulated: %s - %s\n", action.Type, taskStep.Description)
+	} else {
+		fmt.Printf("🎬 Action: %s - %s\n", action.Type, taskStep.Description)
+	}
 
 	currentURL := ""
+	fingerprint := ""
 
 	if state, err := s.browser.GetPageState(ctx); err == nil {
 		currentURL = state.URL
+		fingerprint = pageFingerprint(state)
 	}
 
-	if s.isDuplicateAction(action) {
+	if loop := s.loops.Check(action, fingerprint); loop != nil {
+		stuck := s.loops.MarkStuck()
+		step.AddEvent("loop detected",
+			attribute.String("loop_kind", loop.Kind),
+			attribute.Int("stuck_count", stuck))
+		logger.Warn("Loop detected", zap.String("loop_kind", loop.Kind), zap.Int("stuck_count", stuck))
+
 		taskStep.Success = false
-		taskStep.Error = "duplicate action detected"
+		taskStep.Error = fmt.Sprintf("%s loop detected", loop.Kind)
 		task.Steps = append(task.Steps, taskStep)
 
 		*messages = append(*messages, entity.AIMessage{
 			Role:    "user",
-			Content: "This action failed on the previous attempt. Try a completely different approach.",
+			Content: loop.Message,
 		})
 
-		return apperr.WrapErrorWithReason(op, apperr.CodeDuplicateAction, "duplicate_action")
+		return apperr.Wrap(op, apperr.CodeDuplicateAction, errors.New(taskStep.Error), map[string]any{
+			apperr.MetaReason: "loop_detected",
+			"loop_kind":       loop.Kind,
+			"stuck_count":     stuck,
+		})
 	}
 
-	if s.shouldConfirm(action, currentURL) {
-		if !s.requestUserConfirmation(action) {
+	decision := s.evaluatePolicy(ctx, action, currentURL)
+
+	switch decision.Outcome {
+	case policy.OutcomeDeny:
+		step.AddEvent("action denied by policy", attribute.String("policy_rule", decision.Rule))
+		logger.Warn("Action denied by policy", zap.String("policy_rule", decision.Rule))
+
+		taskStep.Success = false
+		taskStep.Error = "action denied by policy"
+		task.Steps = append(task.Steps, taskStep)
+
+		*messages = append(*messages, entity.AIMessage{
+			Role:    "user",
+			Content: fmt.Sprintf("Action is not allowed by the security policy (%s). Do not retry it; find another way or complete the task explaining what is blocked.", policyReason(decision)),
+		})
+
+		return apperr.Wrap(op, apperr.CodePolicyDenied, errors.New("action denied by policy"), map[string]any{
+			apperr.MetaReason: "policy_denied",
+			"policy_rule":     decision.Rule,
+		})
+	case policy.OutcomeConfirm:
+		if taskStep.Simulated {
+			logger.Info("Dry-run: skipping confirmation", zap.String("policy_rule", decision.Rule))
+
+			break
+		}
+
+		if !s.requestUserConfirmation(ctx, action, decision) {
 			taskStep.Success = false
 			taskStep.Error = "action cancelled by user"
 			task.Steps = append(task.Steps, taskStep)
@@ -288,12 +483,12 @@ func (s *AgentService) handleAction(
 		if err != nil {
 			logger.Error("Action failed", zap.Error(err))
 			taskStep.Success = false
-			taskStep.Error = err.Error()
+			taskStep.Error = s.secrets.Mask(s.redactor.String(err.Error()))
 			task.Steps = append(task.Steps, taskStep)
 
-			s.lastAction = action
+			s.loops.Record(action, fingerprint)
 
-			errorMsg := fmt.Sprintf("Action '%s' failed: %v.", action.Type, err)
+			errorMsg := fmt.Sprintf("Action '%s' failed: %s.", action.Type, taskStep.Error)
 			
 			if action.Type == entity.ActionTypeClick {
 				errorMsg += " Use click_at_coordinates(x, y) with coordinates from the element list instead."
@@ -307,11 +502,21 @@ func (s *AgentService) handleAction(
 			return err
 		}
 
-	s.lastAction = action
+	s.loops.Record(action, fingerprint)
 	taskStep.Success = true
 	task.Steps = append(task.Steps, taskStep)
 
+	if notice := s.newTabsNotice(); notice != "" {
+		result = notice + "\n" + result
+	}
+
+	if notice := s.collectDownloads(task); notice != "" {
+		result = notice + "\n" + result
+	}
+
 	if result != "" {
+		result = s.forModel(result)
+
 		if screenshot != nil && len(screenshot) > 0 {
 			fmt.Printf("📸 Screenshot taken\n")
 		}
@@ -323,86 +528,89 @@ func (s *AgentService) handleAction(
 	return nil
 }
 
-func (s *AgentService) isDuplicateAction(action *entity.BrowserAction) bool {
-	if s.lastAction == nil {
-		return false
-	}
+// forModel masks stored secrets and, with REDACT_PAGE_TEXT, personal data in
+// text about to be sent to the model. Secrets are masked whatever the
+// redaction settings, since they may have been typed into the page.
+func (s *AgentService) forModel(text string) string {
+	text = s.secrets.Mask(text)
 
-	if s.lastAction.Type != action.Type {
-		return false
+	if s.config.RedactionConfig.PageText {
+		text = s.redactor.String(text)
 	}
 
-	switch action.Type {
-	case entity.ActionTypeNavigate:
-		return s.lastAction.URL == action.URL
-	case entity.ActionTypeClick:
-		return s.lastAction.Selector == action.Selector
-	case entity.ActionTypeFill:
-		return s.lastAction.Selector == action.Selector && s.lastAction.Value == action.Value
-	case entity.ActionTypeScroll:
-		return s.lastAction.Value == action.Value && s.lastAction.WaitFor == action.WaitFor
-	case entity.ActionTypeClickCoordinates:
-		return s.lastAction.X == action.X && s.lastAction.Y == action.Y
-	default:
-		return false
-	}
+	return text
 }
 
-func (s *AgentService) shouldConfirm(action *entity.BrowserAction, currentURL string) bool {
-	switch action.Type {
-	case entity.ActionTypeFill:
-		lower := strings.ToLower(action.Selector)
-		lowerValue := strings.ToLower(action.Value)
+func (s *AgentService) evaluatePolicy(ctx context.Context, action *entity.BrowserAction, currentURL string) policy.Decision {
+	subject := policy.Subject{
+		Action: action.Type,
+		URL:    currentURL,
+		Value:  action.Value,
+	}
 
-		if strings.Contains(lower, "password") || 
-		   strings.Contains(lower, "card") || 
-		   strings.Contains(lower, "cvv") ||
-		   strings.Contains(lower, "pin") ||
-		   strings.Contains(lower, "code") && len(action.Value) <= 6 {
-			return true
+	switch action.Type {
+	case entity.ActionTypeNavigate, entity.ActionTypeNewTab:
+		subject.URL = action.URL
+	case entity.ActionTypeClick, entity.ActionTypeFill, entity.ActionTypeUploadFile,
+		entity.ActionTypeSelect, entity.ActionTypeCheck, entity.ActionTypeUncheck, entity.ActionTypeDrag:
+		if elem, err := s.browser.DescribeElement(ctx, action.Selector); err == nil {
+			subject.Element = elem
+		} else {
+			subject.Element = &entity.Element{Selector: action.Selector, Attributes: map[string]string{}}
 		}
-
-		if strings.Contains(lowerValue, "delete") || 
-		   strings.Contains(lowerValue, "remove") ||
-		   strings.Contains(lowerValue, "удалить") {
-			return true
+	case entity.ActionTypeClickCoordinates:
+		if elem, err := s.browser.ElementAt(ctx, action.X, action.Y); err == nil {
+			subject.Element = elem
+		} else {
+			s.logger.Warn("Failed to resolve element at coordinates", zap.Error(err))
+		}
+	case entity.ActionTypePress:
+		if elem, err := s.browser.DescribeElement(ctx, ":focus"); err == nil {
+			subject.Element = elem
+		}
+	case entity.ActionTypeTypeText:
+		selector := action.Selector
+		if selector == "" {
+			selector = ":focus"
 		}
-	case entity.ActionTypeClick:
-		lower := strings.ToLower(action.Selector)
-		urlLower := strings.ToLower(currentURL)
 
-		if (strings.Contains(lower, "delete") || 
-		    strings.Contains(lower, "remove") ||
-		    strings.Contains(lower, "удалить") ||
-		    strings.Contains(lower, "pay") ||
-		    strings.Contains(lower, "оплат") ||
-		    strings.Contains(lower, "купить") ||
-		    strings.Contains(lower, "buy")) &&
-		   (strings.Contains(urlLower, "payment") ||
-		    strings.Contains(urlLower, "checkout") ||
-		    strings.Contains(urlLower, "cart") ||
-		    strings.Contains(urlLower, "оплата")) {
-			return true
+		if elem, err := s.browser.DescribeElement(ctx, selector); err == nil {
+			subject.Element = elem
+		} else {
+			subject.Element = &entity.Element{Selector: selector, Attributes: map[string]string{}}
+		}
+	case entity.ActionTypeHandleDialog:
+		if dialog := s.browser.PendingDialog(); dialog != nil && action.Accept {
+			subject.Element = &entity.Element{Tag: "dialog", Type: dialog.Type, Text: dialog.Message, Attributes: map[string]string{}}
 		}
 	}
 
-	return false
+	return s.policy.Evaluate(subject)
 }
 
-func (s *AgentService) requestUserConfirmation(action *entity.BrowserAction) bool {
-	fmt.Printf("\n⚠️  Security confirmation required\n")
-	fmt.Printf("Action: %s %s\n", action.Type, s.formatActionDescription(action))
-	fmt.Print("Confirm (yes/no): ")
+func policyReason(decision policy.Decision) string {
+	if decision.Reason != "" {
+		return decision.Reason
+	}
 
-	scanner := bufio.NewScanner(os.Stdin)
+	if decision.Rule != "" {
+		return "rule " + decision.Rule
+	}
 
-	if scanner.Scan() {
-		response := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	return "default policy"
+}
 
-		return response == "yes" || response == "y"
+func (s *AgentService) requestUserConfirmation(ctx context.Context, action *entity.BrowserAction, decision policy.Decision) bool {
+	fmt.Printf("\n⚠️  Security confirmation required\n")
+	fmt.Printf("Action: %s %s\n", action.Type, s.formatActionDescription(action))
+	fmt.Printf("Reason: %s\n", policyReason(decision))
+
+	confirmed, err := s.operator.Confirm(ctx, "Confirm (yes/no): ")
+	if err != nil {
+		return false
 	}
 
-	return false
+	return confirmed
 }
 
 func (s *AgentService) formatActionDescription(action *entity.BrowserAction) string {
@@ -412,7 +620,13 @@ func (s *AgentService) formatActionDescription(action *entity.BrowserAction) str
 	case entity.ActionTypeClick:
 		return fmt.Sprintf("selector: %s", action.Selector)
 	case entity.ActionTypeFill:
-		return fmt.Sprintf("selector: %s, value: %s", action.Selector, action.Value)
+		return fmt.Sprintf("selector: %s, value: %s", action.Selector, s.secrets.Mask(action.Value))
+	case entity.ActionTypeTypeText:
+		if action.Selector == "" {
+			return fmt.Sprintf("focused element, text: %s", s.secrets.Mask(action.Value))
+		}
+
+		return fmt.Sprintf("selector: %s, text: %s", action.Selector, s.secrets.Mask(action.Value))
 	case entity.ActionTypePress:
 		return fmt.Sprintf("key: %s", action.Value)
 	case entity.ActionTypeWait:
@@ -429,23 +643,95 @@ func (s *AgentService) formatActionDescription(action *entity.BrowserAction) str
 			amount = action.WaitFor
 		}
 
+		if action.Selector != "" {
+			return fmt.Sprintf("selector: %s, direction: %s, amount: %d", action.Selector, direction, amount)
+		}
+
 		return fmt.Sprintf("direction: %s, amount: %d", direction, amount)
+	case entity.ActionTypeScrollUntil:
+		var until []string
+
+		if action.Value != "" {
+			until = append(until, fmt.Sprintf("text: %s", action.Value))
+		}
+
+		if action.Target != "" {
+			until = append(until, fmt.Sprintf("selector: %s", action.Target))
+		}
+
+		if action.Limit > 0 {
+			until = append(until, fmt.Sprintf("count: %d", action.Limit))
+		}
+
+		if len(until) == 0 {
+			until = append(until, "end of content")
+		}
+
+		if action.Selector != "" {
+			return fmt.Sprintf("container: %s, until %s", action.Selector, strings.Join(until, ", "))
+		}
+
+		return fmt.Sprintf("until %s", strings.Join(until, ", "))
 	case entity.ActionTypeClickCoordinates:
 		return fmt.Sprintf("x: %.0f, y: %.0f", action.X, action.Y)
+	case entity.ActionTypeAskUser:
+		return fmt.Sprintf("question: %s", action.Value)
+	case entity.ActionTypeSwitchTab, entity.ActionTypeCloseTab:
+		return fmt.Sprintf("tab: %d", action.TabID)
+	case entity.ActionTypeReadPage:
+		if action.Selector == "" {
+			return fmt.Sprintf("main content, page: %d", max(action.Page, 1))
+		}
+
+		return fmt.Sprintf("selector: %s, page: %d", action.Selector, max(action.Page, 1))
+	case entity.ActionTypeExtractData:
+		if action.Selector == "" {
+			return fmt.Sprintf("auto-detect, limit: %d", action.Limit)
+		}
+
+		return fmt.Sprintf("selector: %s, limit: %d", action.Selector, action.Limit)
+	case entity.ActionTypeFindText:
+		return fmt.Sprintf("query: %s, exact: %t, scroll: %t", action.Value, action.Exact, action.ScrollIntoView)
+	case entity.ActionTypeNewTab:
+		return action.URL
+	case entity.ActionTypeSelect:
+		return fmt.Sprintf("selector: %s, option: %s", action.Selector, action.Value)
+	case entity.ActionTypeCheck, entity.ActionTypeUncheck, entity.ActionTypeHover:
+		return fmt.Sprintf("selector: %s", action.Selector)
+	case entity.ActionTypeDrag:
+		if action.Target != "" {
+			return fmt.Sprintf("from: %s, to: %s", action.Selector, action.Target)
+		}
+
+		return fmt.Sprintf("from: %s, to: x: %.0f, y: %.0f", action.Selector, action.X, action.Y)
+	case entity.ActionTypeUploadFile:
+		return fmt.Sprintf("selector: %s, file: %s", action.Selector, action.Value)
+	case entity.ActionTypeHandleDialog:
+		if action.Accept {
+			return fmt.Sprintf("accept, text: %s", s.secrets.Mask(action.Value))
+		}
+
+		return "dismiss"
 	default:
 		return ""
 	}
 }
 
-func (s *AgentService) truncateText(text string, maxLen int) string {
+// truncateText cuts text to at most maxLen runes, so multi-byte text is
+// never split mid-character.
+func truncateText(text string, maxLen int) string {
 	if len(text) <= maxLen {
 		return text
 	}
 
-	return text[:maxLen] + "..."
+	if runes := []rune(text); len(runes) > maxLen {
+		return string(runes[:maxLen]) + "..."
+	}
+
+	return text
 }
 
-func (s *AgentService) buildSystemPrompt(taskDescription string) string {
+func (s *AgentService) buildSystemPrompt(taskDescription string, resultSchema map[string]interface{}) string {
 	var prompt strings.Builder
 
 	prompt.WriteString("You are a browser automation agent. Complete tasks efficiently.\n\n")
@@ -453,25 +739,78 @@ func (s *AgentService) buildSystemPrompt(taskDescription string) string {
 
 	prompt.WriteString(`Available actions:
 - navigate(url)
-- click_at_coordinates(x, y) - PRIMARY method, click at screen position
-- click(selector) - backup method if coordinates not available
-- fill(selector, value) - auto-submits search fields
-- press(key)
-- scroll(direction, amount)
+- go_back() / go_forward() / reload() - browser history; go_back undoes an accidental navigation and keeps form state
+- click(element_id) - PRIMARY method, use the [id] from the element list (a selector also works)
+- click_at_coordinates(x, y) - fallback when the id is stale or the element is not listed
+- fill(element_id, value, type) - auto-submits search fields (a selector also works); type=true clears and types key by key instead of setting the value
+- select_option(element_id, value) - pick an option in a <select> or custom dropdown by its label or value
+- check(element_id) / uncheck(element_id) - set a checkbox, radio button or switch; the new state is verified
+- hover(element_id) - move the mouse over an element to open menus and tooltips
+- drag(element_id, target_element_id or x/y) - drag sliders, sortable list items and draggable cards
+- type_text(element_id, text, delay_ms, clear) - type key by key for autocomplete, masked inputs (phone, card) and key-driven widgets; embed keys as {Enter} or {Control+A}; lists the suggestions that appear
+- press(key) - a key, chord or sequence, e.g. "Enter", "Control+A", "Control+A Backspace"
+- scroll(direction, amount, element_id) - scrolls the main pane under the viewport center; pass element_id to scroll a specific panel, list or modal
+- scroll_until(text | until_selector | count, element_id) - keep scrolling an infinite feed until the text appears, the CSS selector matches count elements, or the list holds count items; stops when nothing more loads
+- list_tabs() / switch_tab(tab_id) / close_tab(tab_id) / new_tab(url) - links and logins may open new tabs
+- upload_file(selector, file_alias) - attach an approved file to a file input or upload button
+- handle_dialog(accept, prompt_text) - answer an open alert/confirm/prompt; the page is blocked until you do
+- read_page(element_id, page) - read the full text of the page (or one element) as Markdown, in pages
+- extract_data(element_id, limit) - get repeated records (tables, result lists, cards) as JSON rows; use it to collect lists of items
+- find_text(query, exact, scroll) - search the whole page (also offscreen) for text and get element ids; use it instead of scrolling blindly
+- ask_user(question) - hand control to the human for CAPTCHA, 2FA codes, unfamiliar widgets or missing info
 - complete_task(result)
 
 IMPORTANT RULES:
-1. Clickable elements show: text | selector | coords (x,y) | size WxH
-2. Coordinates are CENTER of element - use these for clicking
-3. ALWAYS prefer click_at_coordinates(x,y) - it's more reliable
+1. Clickable elements show: [id] [tag] text | selector (short ones only) | coords (x,y) | size WxH
+2. Ids stay the same while the element is on the page - never invent ids
+3. Coordinates are the CENTER of the element - use them with click_at_coordinates if an id fails
 4. After EVERY click you get screenshot - check what happened
 5. Look for [ICON_BUTTON], [*_CARD_*], [*_ITEM_*] patterns
 6. Search fields auto-submit with Enter
 7. NEVER repeat failed actions
 8. Before completing - VERIFY result (check cart, confirmation, new elements)
 9. Only complete when you SEE proof of success
+10. Selectors with " >>> " point inside an iframe - pass them to click/fill unchanged
+11. On the same page you only get the changes since the last step - elements not mentioned are unchanged
 
 Max 16 iterations.`)
 
+	if s.config.BrowserConfig.PageState == pageStateAccessibility {
+		prompt.WriteString("\n\nPages are described by their accessibility tree (role \"name\" [states]). Prefer click(selector)/fill(selector, value) with the given role selectors, e.g. selector role=button[name=\"Search\"]; they are more stable than coordinates.")
+	}
+
+	if s.config.BrowserConfig.ScreenshotMarks && s.config.BrowserConfig.PageState != pageStateAccessibility {
+		prompt.WriteString("\n\nScreenshots show a numbered box over each listed clickable element. The number is the element's [id]; use click(element_id) rather than guessing coordinates from the image.")
+	}
+
+	if s.dryRun {
+		prompt.WriteString("\n\nDRY-RUN MODE: " + simulatedTools() + " are simulated and do not change the page. navigate and scroll are real. Plan the full sequence of actions you would take, then complete_task with a summary of that plan.")
+	}
+
+	if names := s.secrets.Names(); len(names) > 0 {
+		prompt.WriteString("\n\nCredentials are never shown to you. To type one, pass its placeholder as the fill value, e.g. fill(selector, \"")
+		prompt.WriteString(secrets.Placeholder(names[0]))
+		prompt.WriteString("\").\nAvailable secrets: ")
+
+		placeholders := make([]string, len(names))
+		for i, name := range names {
+			placeholders[i] = secrets.Placeholder(name)
+		}
+
+		prompt.WriteString(strings.Join(placeholders, ", "))
+	}
+
+	if aliases := s.files.Aliases(); len(aliases) > 0 {
+		prompt.WriteString("\n\nFiles you may upload with upload_file(selector, file_alias): ")
+		prompt.WriteString(strings.Join(aliases, ", "))
+	}
+
+	if resultSchema != nil {
+		if encoded, err := json.Marshal(resultSchema); err == nil {
+			prompt.WriteString("\n\nThe result passed to complete_task MUST be JSON matching this schema (no prose):\n")
+			prompt.WriteString(string(encoded))
+		}
+	}
+
 	return prompt.String()
 }
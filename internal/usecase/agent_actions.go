@@ -9,6 +9,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -26,6 +27,14 @@ func (s *AgentService) executeAction(ctx context.Context, action *entity.Browser
 		step.End(err)
 	}()
 
+	if err := s.dialogBlocksAction(action); err != nil {
+		return "", nil, err
+	}
+
+	if s.isSimulated(action) {
+		return s.actionSimulate(ctx, action)
+	}
+
 	switch action.Type {
 	case entity.ActionTypeNavigate:
 		return s.actionNavigate(ctx, action)
@@ -41,6 +50,40 @@ func (s *AgentService) executeAction(ctx context.Context, action *entity.Browser
 		return s.actionClickCoordinates(ctx, action)
 	case entity.ActionTypePress:
 		return s.actionPress(ctx, action)
+	case entity.ActionTypeAskUser:
+		return s.actionAskUser(ctx, action)
+	case entity.ActionTypeListTabs:
+		return s.actionListTabs(ctx, action)
+	case entity.ActionTypeSwitchTab:
+		return s.actionSwitchTab(ctx, action)
+	case entity.ActionTypeCloseTab:
+		return s.actionCloseTab(ctx, action)
+	case entity.ActionTypeNewTab:
+		return s.actionNewTab(ctx, action)
+	case entity.ActionTypeHandleDialog:
+		return s.actionHandleDialog(ctx, action)
+	case entity.ActionTypeUploadFile:
+		return s.actionUploadFile(ctx, action)
+	case entity.ActionTypeReadPage:
+		return s.actionReadPage(ctx, action)
+	case entity.ActionTypeExtractData:
+		return s.actionExtractData(ctx, action)
+	case entity.ActionTypeFindText:
+		return s.actionFindText(ctx, action)
+	case entity.ActionTypeGoBack, entity.ActionTypeGoForward, entity.ActionTypeReload:
+		return s.actionHistory(ctx, action)
+	case entity.ActionTypeSelect:
+		return s.actionSelectOption(ctx, action)
+	case entity.ActionTypeCheck, entity.ActionTypeUncheck:
+		return s.actionSetChecked(ctx, action)
+	case entity.ActionTypeHover:
+		return s.actionHover(ctx, action)
+	case entity.ActionTypeDrag:
+		return s.actionDrag(ctx, action)
+	case entity.ActionTypeTypeText:
+		return s.actionTypeText(ctx, action)
+	case entity.ActionTypeScrollUntil:
+		return s.actionScrollUntil(ctx, action)
 	default:
 		return "", nil, apperr.WrapErrorWithReason(op, apperr.CodeInvalidArgument, "unknown_action_type")
 	}
@@ -60,6 +103,16 @@ func (s *AgentService) actionNavigate(ctx context.Context, action *entity.Browse
 		return "", nil, apperr.InvalidReqError(op, "url", fmt.Errorf("url cannot be empty"))
 	}
 
+	if allowed, reason := s.urlFilter.Check(action.URL); !allowed {
+		step.AddEvent("navigation blocked by policy")
+
+		return "", nil, apperr.Wrap(op, apperr.CodePolicyDenied, fmt.Errorf("navigation to %s is blocked: %s", action.URL, reason), map[string]any{
+			apperr.MetaReason: "navigation_denied",
+			apperr.MetaStage:  apperr.StageNavigation,
+			apperr.MetaURL:    action.URL,
+		})
+	}
+
 	step.AddEvent("navigating to URL")
 
 	if err := s.browser.Navigate(ctx, action.URL); err != nil {
@@ -80,10 +133,18 @@ func (s *AgentService) actionNavigate(ctx context.Context, action *entity.Browse
 		})
 	}
 
+	state, notice, err := s.enforceNavigationPolicy(ctx, state, s.lastURL)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "page_state_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
 	s.lastURL = state.URL
 	screenshot, _ = s.takeScreenshot(ctx)
 
-	return s.optimizePageState(state), screenshot, nil
+	return notice + s.optimizePageState(state), screenshot, nil
 }
 
 func (s *AgentService) actionClick(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
@@ -97,7 +158,7 @@ func (s *AgentService) actionClick(ctx context.Context, action *entity.BrowserAc
 	}()
 
 	if action.Selector == "" {
-		return "", nil, apperr.InvalidReqError(op, "selector", fmt.Errorf("selector cannot be empty"))
+		return "", nil, apperr.InvalidReqError(op, "selector", fmt.Errorf("selector or element_id is required"))
 	}
 
 	oldURL := s.lastURL
@@ -122,6 +183,14 @@ func (s *AgentService) actionClick(ctx context.Context, action *entity.BrowserAc
 		})
 	}
 
+	state, notice, err := s.enforceNavigationPolicy(ctx, state, oldURL)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "page_state_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
 	newURL := state.URL
 	s.lastURL = newURL
 
@@ -129,7 +198,32 @@ func (s *AgentService) actionClick(ctx context.Context, action *entity.BrowserAc
 		screenshot, _ = s.takeScreenshot(ctx)
 	}
 
-	return s.optimizePageState(state), screenshot, nil
+	return notice + s.optimizePageState(state), screenshot, nil
+}
+
+var searchFieldPattern = regexp.MustCompile(`(?i)search|query|поиск|найти|(^|[^a-z])q([^a-z]|$)`)
+
+// isSearchField reports whether a filled field is a search box: type=search,
+// role=searchbox, or a name, id, placeholder or label that says so. A CSS
+// selector is checked too when the element could not be described.
+func isSearchField(elem *entity.Element, selector string) bool {
+	if elem == nil {
+		return !strings.HasPrefix(selector, "ref=") && searchFieldPattern.MatchString(selector)
+	}
+
+	attrs := elem.Attributes
+
+	if strings.EqualFold(attrs["type"], "search") || strings.EqualFold(attrs["role"], "searchbox") {
+		return true
+	}
+
+	for _, name := range []string{"name", "id", "placeholder", "aria-label", "title"} {
+		if attrs[name] != "" && searchFieldPattern.MatchString(attrs[name]) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (s *AgentService) actionFill(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
@@ -143,12 +237,34 @@ func (s *AgentService) actionFill(ctx context.Context, action *entity.BrowserAct
 	}()
 
 	if action.Selector == "" {
-		return "", nil, apperr.InvalidReqError(op, "selector", fmt.Errorf("selector cannot be empty"))
+		return "", nil, apperr.InvalidReqError(op, "selector", fmt.Errorf("selector or element_id is required"))
 	}
 
-	step.AddEvent("filling field")
+	value := action.Value
+
+	if s.secrets.HasPlaceholder(value) {
+		step.AddEvent("substituting secrets")
+
+		value, err = s.secrets.Resolve(value)
+		if err != nil {
+			return "", nil, apperr.InvalidReqError(op, "value", err)
+		}
+	}
+
+	var typed *entity.TypingResult
+
+	if action.SimulateTyping {
+		step.AddEvent("clearing and typing field")
+
+		// fill types its value literally, braces included.
+		typed, err = s.browser.TypeText(ctx, action.Selector, []entity.TypingSegment{{Text: value}}, action.Delay, true)
+	} else {
+		step.AddEvent("filling field")
+
+		err = s.browser.Fill(ctx, action.Selector, value)
+	}
 
-	if err := s.browser.Fill(ctx, action.Selector, action.Value); err != nil {
+	if err != nil {
 		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
 			apperr.MetaReason:   "fill_failed",
 			apperr.MetaStage:    apperr.StageInteraction,
@@ -156,11 +272,11 @@ func (s *AgentService) actionFill(ctx context.Context, action *entity.BrowserAct
 		})
 	}
 
-	isSearchField := strings.Contains(strings.ToLower(action.Selector), "search") ||
-		strings.Contains(strings.ToLower(action.Selector), "query") ||
-		strings.Contains(strings.ToLower(action.Value), "поиск")
+	// Element refs hide the field's attributes, so look at the element
+	// itself rather than the selector text.
+	elem, _ := s.browser.DescribeElement(ctx, action.Selector)
 
-	if isSearchField {
+	if isSearchField(elem, action.Selector) || strings.Contains(strings.ToLower(action.Value), "поиск") {
 		logger.Info("Auto-pressing Enter for search field")
 		step.AddEvent("auto-pressing Enter for search")
 
@@ -172,11 +288,20 @@ func (s *AgentService) actionFill(ctx context.Context, action *entity.BrowserAct
 			return "Field filled (Enter press failed).", nil, nil
 		}
 
-		time.Sleep(1500 * time.Millisecond)
-
 		state, err := s.browser.GetPageState(ctx)
 		if err != nil {
-			return "Field filled and Enter pressed.", nil, nil
+			return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+				apperr.MetaReason: "page_state_failed",
+				apperr.MetaStage:  apperr.StagePageState,
+			})
+		}
+
+		state, notice, err := s.enforceNavigationPolicy(ctx, state, oldURL)
+		if err != nil {
+			return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+				apperr.MetaReason: "page_state_failed",
+				apperr.MetaStage:  apperr.StagePageState,
+			})
 		}
 
 		newURL := state.URL
@@ -186,7 +311,11 @@ func (s *AgentService) actionFill(ctx context.Context, action *entity.BrowserAct
 			screenshot, _ = s.takeScreenshot(ctx)
 		}
 
-		return s.optimizePageState(state), screenshot, nil
+		return notice + s.optimizePageState(state), screenshot, nil
+	}
+
+	if typed != nil {
+		return "Field filled by typing. " + s.formatTypingResult(typed), nil, nil
 	}
 
 	return "Field filled.", nil, nil
@@ -222,10 +351,12 @@ func (s *AgentService) actionScroll(ctx context.Context, action *entity.BrowserA
 
 	step.AddEvent("scrolling page")
 
-	if err := s.browser.Scroll(ctx, direction, amount); err != nil {
+	scrolled, err := s.browser.Scroll(ctx, action.Selector, direction, amount)
+	if err != nil {
 		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
-			apperr.MetaReason: "scroll_failed",
-			apperr.MetaStage:  apperr.StageInteraction,
+			apperr.MetaReason:   "scroll_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: action.Selector,
 		})
 	}
 
@@ -239,7 +370,7 @@ func (s *AgentService) actionScroll(ctx context.Context, action *entity.BrowserA
 		})
 	}
 
-	return s.optimizePageState(state), nil, nil
+	return formatScrollResult(scrolled) + s.optimizePageState(state), nil, nil
 }
 
 func (s *AgentService) actionClickCoordinates(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
@@ -253,6 +384,8 @@ func (s *AgentService) actionClickCoordinates(ctx context.Context, action *entit
 		step.End(err)
 	}()
 
+	oldURL := s.lastURL
+
 	step.AddEvent("clicking at coordinates")
 
 	if err := s.browser.ClickAtCoordinates(ctx, action.X, action.Y); err != nil {
@@ -262,8 +395,6 @@ func (s *AgentService) actionClickCoordinates(ctx context.Context, action *entit
 		})
 	}
 
-	time.Sleep(800 * time.Millisecond)
-
 	step.AddEvent("getting page state")
 
 	state, err := s.browser.GetPageState(ctx)
@@ -274,11 +405,19 @@ func (s *AgentService) actionClickCoordinates(ctx context.Context, action *entit
 		})
 	}
 
+	state, notice, err := s.enforceNavigationPolicy(ctx, state, oldURL)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "page_state_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
 	s.lastURL = state.URL
 
 	screenshot, _ = s.takeScreenshot(ctx)
 
-	return s.optimizePageState(state), screenshot, nil
+	return notice + s.optimizePageState(state), screenshot, nil
 }
 
 func (s *AgentService) actionPress(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
@@ -306,8 +445,8 @@ func (s *AgentService) actionPress(ctx context.Context, action *entity.BrowserAc
 		})
 	}
 
-	// For Enter key, get updated page state
-	if action.Value == "Enter" {
+	// For Enter key (alone or ending a sequence), get updated page state
+	if keys := strings.Fields(action.Value); len(keys) > 0 && keys[len(keys)-1] == "Enter" {
 		step.AddEvent("getting page state after Enter")
 
 		state, err := s.browser.GetPageState(ctx)
@@ -318,6 +457,14 @@ func (s *AgentService) actionPress(ctx context.Context, action *entity.BrowserAc
 			})
 		}
 
+		state, notice, err := s.enforceNavigationPolicy(ctx, state, oldURL)
+		if err != nil {
+			return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+				apperr.MetaReason: "page_state_failed",
+				apperr.MetaStage:  apperr.StagePageState,
+			})
+		}
+
 		newURL := state.URL
 		s.lastURL = newURL
 
@@ -325,12 +472,56 @@ func (s *AgentService) actionPress(ctx context.Context, action *entity.BrowserAc
 			screenshot, _ = s.takeScreenshot(ctx)
 		}
 
-		return s.optimizePageState(state), screenshot, nil
+		return notice + s.optimizePageState(state), screenshot, nil
 	}
 
 	return fmt.Sprintf("Pressed key: %s", action.Value), nil, nil
 }
 
+// enforceNavigationPolicy undoes navigations that were triggered by the page
+// itself (links, form submits, redirects) and landed on a blocked URL.
+func (s *AgentService) enforceNavigationPolicy(ctx context.Context, state *entity.PageState, previousURL string) (*entity.PageState, string, error) {
+	allowed, reason := s.urlFilter.Check(state.URL)
+	if allowed {
+		return state, "", nil
+	}
+
+	blockedURL := state.URL
+	s.logger.Warn("Navigated to blocked URL, going back",
+		zap.String(logg.URL, blockedURL),
+		zap.String("reason", reason))
+
+	if err := s.browser.GoBack(ctx); err != nil {
+		s.logger.Warn("Failed to go back", zap.Error(err))
+	}
+
+	state, err := s.browser.GetPageState(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if ok, _ := s.urlFilter.Check(state.URL); !ok {
+		fallback := "about:blank"
+
+		if ok, _ := s.urlFilter.Check(previousURL); ok && previousURL != "" {
+			fallback = previousURL
+		}
+
+		if err := s.browser.Navigate(ctx, fallback); err != nil {
+			return nil, "", err
+		}
+
+		if state, err = s.browser.GetPageState(ctx); err != nil {
+			return nil, "", err
+		}
+	}
+
+	notice := fmt.Sprintf("⚠️ The action opened %s, which is blocked by the navigation policy (%s). "+
+		"Went back to %s. Do not follow that link again.\n\n", blockedURL, reason, state.URL)
+
+	return state, notice, nil
+}
+
 func (s *AgentService) takeScreenshot(ctx context.Context) ([]byte, error) {
 	if !s.browser.IsReady() {
 		return nil, fmt.Errorf("browser not ready")
@@ -361,44 +552,52 @@ func (s *AgentService) optimizePageState(state *entity.PageState) string {
 
 	result.WriteString(fmt.Sprintf("URL: %s\n", state.URL))
 	result.WriteString(fmt.Sprintf("Title: %s\n\n", state.Title))
+	result.WriteString(formatDialogs(state.Dialogs))
+	s.browser.AckDialogs(state.Dialogs)
+
+	if state.FileChooserOpen {
+		result.WriteString("📎 A file chooser is open: call upload_file with an empty selector and a file_alias\n\n")
+	}
+
+	previous := s.lastState
+	s.lastState = state
+
+	if len(state.AXNodes) > 0 {
+		result.WriteString(formatAccessibilityTree(state.AXNodes))
+
+		return result.String()
+	}
 
 	if len(state.Elements) == 0 {
 		return result.String()
 	}
 
-	clickableElems := []entity.Element{}
-	otherElems := []entity.Element{}
+	if delta, ok := diffPageState(previous, state); ok {
+		result.WriteString(delta)
 
-	for _, elem := range state.Elements {
-		if elem.Clickable {
-			clickableElems = append(clickableElems, elem)
-		} else if elem.Text != "" && len(elem.Text) >= 3 {
-			otherElems = append(otherElems, elem)
-		}
+		return result.String()
 	}
 
+	clickableElems, otherElems := listedElements(state.Elements)
+
 	result.WriteString("Clickable elements:\n")
 	count := 0
 
 	for _, elem := range clickableElems {
-		if count >= 40 {
-			break
-		}
-
 		text := elem.Text
 		if len(text) > 200 {
 			text = text[:200] + "..."
 		}
 
-		selector := elem.Selector
-		if len(selector) > 100 {
-			selector = selector[:100] + "..."
+		selector := ""
+		if len(elem.Selector) <= 100 {
+			selector = " | selector: " + elem.Selector
 		}
 
 		count++
 
-		result.WriteString(fmt.Sprintf("%d. [%s] %s | selector: %s | coords: (%.0f,%.0f) size: %.0fx%.0f\n", 
-			count, elem.Tag, text, selector, elem.BoundingBox.X, elem.BoundingBox.Y, elem.BoundingBox.Width, elem.BoundingBox.Height))
+		result.WriteString(fmt.Sprintf("%s [%s] %s%s | coords: (%.0f,%.0f) size: %.0fx%.0f\n",
+			elementLabel(elem, count), elem.Tag, text, selector, elem.BoundingBox.X, elem.BoundingBox.Y, elem.BoundingBox.Width, elem.BoundingBox.Height))
 	}
 
 	if len(otherElems) > 0 {
@@ -406,23 +605,27 @@ func (s *AgentService) optimizePageState(state *entity.PageState) string {
 		otherCount := 0
 
 		for _, elem := range otherElems {
-			if otherCount >= 10 {
-				break
-			}
-
 			text := elem.Text
 			if len(text) > 200 {
 				text = text[:200] + "..."
 			}
 
 			otherCount++
-			result.WriteString(fmt.Sprintf("%d. [%s] %s\n", otherCount, elem.Tag, text))
+			result.WriteString(fmt.Sprintf("%s [%s] %s\n", elementLabel(elem, otherCount), elem.Tag, text))
 		}
 	}
 
 	return result.String()
 }
 
+func elementLabel(elem entity.Element, position int) string {
+	if elem.ID > 0 {
+		return fmt.Sprintf("[%d]", elem.ID)
+	}
+
+	return fmt.Sprintf("%d.", position)
+}
+
 func (s *AgentService) createMessageWithScreenshot(role, text string, screenshot []byte) entity.AIMessage {
 	if screenshot == nil || len(screenshot) == 0 {
 		return entity.AIMessage{
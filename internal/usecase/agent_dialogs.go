@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) actionHandleDialog(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionHandleDialog"
+	logger := s.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.Bool("accept", action.Accept))
+	defer func() {
+		step.End(err)
+	}()
+
+	accept := action.Accept
+	simulated := s.dryRun && accept
+
+	// In dry-run the page still has to be unblocked, so the dialog is
+	// dismissed instead of accepted.
+	if simulated {
+		step.AddEvent("simulating accept")
+		accept = false
+	}
+
+	promptText, err := s.secrets.Resolve(action.Value)
+	if err != nil {
+		return "", nil, apperr.InvalidReqError(op, "prompt_text", err)
+	}
+
+	dialog, err := s.browser.HandleDialog(ctx, accept, promptText)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "handle_dialog_failed",
+			apperr.MetaStage:  apperr.StageInteraction,
+		})
+	}
+
+	intro := fmt.Sprintf("Dialog %s \"%s\" %s.\n\n", dialog.Type, dialog.Message, dialog.Outcome)
+	if simulated {
+		intro = fmt.Sprintf("SIMULATED: would accept %s \"%s\". It was dismissed to keep the page unchanged.\n\n",
+			dialog.Type, dialog.Message)
+	}
+
+	return s.activeTabState(ctx, op, intro)
+}
+
+// dialogBlocksAction reports an unanswered dialog for actions that would
+// stall behind it.
+func (s *AgentService) dialogBlocksAction(action *entity.BrowserAction) error {
+	switch action.Type {
+	case entity.ActionTypeHandleDialog,
+		entity.ActionTypeAskUser,
+		entity.ActionTypeListTabs,
+		entity.ActionTypeSwitchTab,
+		entity.ActionTypeCloseTab,
+		entity.ActionTypeNewTab,
+		entity.ActionTypeWait:
+		return nil
+	}
+
+	dialog := s.browser.PendingDialog()
+	if dialog == nil {
+		return nil
+	}
+
+	return apperr.Wrap("dialogBlocksAction", apperr.CodeActionFailed,
+		fmt.Errorf("a %s dialog is open in tab %d (\"%s\"), answer it with handle_dialog first", dialog.Type, dialog.TabID, dialog.Message),
+		map[string]any{
+			apperr.MetaReason: "dialog_open",
+			apperr.MetaStage:  apperr.StageInteraction,
+		})
+}
+
+func formatDialogs(dialogs []entity.Dialog) string {
+	if len(dialogs) == 0 {
+		return ""
+	}
+
+	var result strings.Builder
+
+	for _, dialog := range dialogs {
+		if dialog.Outcome == "" {
+			result.WriteString(fmt.Sprintf("⚠️ OPEN %s DIALOG (tab %d): \"%s\"", strings.ToUpper(dialog.Type), dialog.TabID, dialog.Message))
+
+			if dialog.Type == "prompt" {
+				result.WriteString(fmt.Sprintf(" default: \"%s\"", dialog.DefaultValue))
+			}
+
+			result.WriteString(" - the page is blocked until you call handle_dialog(accept, prompt_text)\n")
+
+			continue
+		}
+
+		result.WriteString(fmt.Sprintf("Dialog %s \"%s\" was %s automatically\n", dialog.Type, dialog.Message, dialog.Outcome))
+	}
+
+	result.WriteString("\n")
+
+	return result.String()
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/internal/files"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// collectDownloads records files downloaded during the last action on the
+// task and describes them for the model, including their text if enabled.
+func (s *AgentService) collectDownloads(task *entity.Task) string {
+	downloads := s.browser.DrainDownloads()
+	if len(downloads) == 0 {
+		return ""
+	}
+
+	task.Downloads = append(task.Downloads, downloads...)
+
+	var notice strings.Builder
+
+	for _, download := range downloads {
+		if download.Error != "" {
+			notice.WriteString(fmt.Sprintf("📥 Download of %s failed: %s\n", download.Name, download.Error))
+
+			continue
+		}
+
+		fmt.Printf("📥 Downloaded %s (%s)\n", download.Name, files.FormatSize(download.Size))
+		notice.WriteString(fmt.Sprintf("📥 Downloaded %s (%s)\n", download.Name, files.FormatSize(download.Size)))
+
+		if !s.config.FilesConfig.ExtractText {
+			continue
+		}
+
+		text, truncated, err := files.ExtractText(download.Path, s.config.FilesConfig.ExtractMaxChars)
+		if err != nil {
+			if !errors.Is(err, files.ErrUnsupportedFormat) {
+				s.logger.Warn("Failed to extract download text", zap.String("file_name", download.Name), zap.Error(err))
+			}
+
+			continue
+		}
+
+		if text == "" {
+			notice.WriteString("(no text could be extracted)\n")
+
+			continue
+		}
+
+		notice.WriteString(fmt.Sprintf("Contents of %s:\n%s\n", download.Name, text))
+
+		if truncated {
+			notice.WriteString("...(truncated)\n")
+		}
+	}
+
+	return notice.String()
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) SetDryRun(enabled bool) {
+	s.dryRun = enabled
+}
+
+// simulatedActions are the actions dry-run mode fakes, with the tool name the
+// model knows each one by.
+var simulatedActions = []struct {
+	action entity.ActionType
+	tool   string
+}{
+	{entity.ActionTypeClick, "click"},
+	{entity.ActionTypeClickCoordinates, "click_at_coordinates"},
+	{entity.ActionTypeFill, "fill"},
+	{entity.ActionTypeTypeText, "type_text"},
+	{entity.ActionTypePress, "press"},
+	{entity.ActionTypeSelect, "select_option"},
+	{entity.ActionTypeCheck, "check"},
+	{entity.ActionTypeUncheck, "uncheck"},
+	{entity.ActionTypeDrag, "drag"},
+	{entity.ActionTypeUploadFile, "upload_file"},
+}
+
+func (s *AgentService) isSimulated(action *entity.BrowserAction) bool {
+	if !s.dryRun {
+		return false
+	}
+
+	for _, simulated := range simulatedActions {
+		if simulated.action == action.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+func simulatedTools() string {
+	tools := make([]string, len(simulatedActions))
+	for i, simulated := range simulatedActions {
+		tools[i] = simulated.tool
+	}
+
+	return strings.Join(tools[:len(tools)-1], ", ") + " and " + tools[len(tools)-1]
+}
+
+func (s *AgentService) actionSimulate(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionSimulate"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Action, string(action.Type)))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("action_type", string(action.Type)))
+	defer func() {
+		step.End(err)
+	}()
+
+	step.AddEvent("resolving target element")
+
+	var target *entity.Element
+
+	switch action.Type {
+	case entity.ActionTypeClick, entity.ActionTypeFill, entity.ActionTypeSelect,
+		entity.ActionTypeCheck, entity.ActionTypeUncheck, entity.ActionTypeDrag:
+		if action.Selector == "" {
+			return "", nil, apperr.InvalidReqError(op, "selector", fmt.Errorf("selector or element_id is required"))
+		}
+
+		target, err = s.browser.DescribeElement(ctx, action.Selector)
+		if err != nil {
+			return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+				apperr.MetaReason:   "element_not_found",
+				apperr.MetaStage:    apperr.StageInteraction,
+				apperr.MetaSelector: action.Selector,
+			})
+		}
+	case entity.ActionTypeClickCoordinates:
+		target, err = s.browser.ElementAt(ctx, action.X, action.Y)
+		if err != nil {
+			target = &entity.Element{
+				BoundingBox: entity.BoundingBox{X: action.X, Y: action.Y, Width: 20, Height: 20},
+			}
+		}
+	case entity.ActionTypePress:
+		target, _ = s.browser.DescribeElement(ctx, ":focus")
+	case entity.ActionTypeTypeText:
+		selector := action.Selector
+		if selector == "" {
+			selector = ":focus"
+		}
+
+		target, _ = s.browser.DescribeElement(ctx, selector)
+	case entity.ActionTypeUploadFile:
+		if _, err := s.files.Resolve(action.Value); err != nil {
+			return "", nil, apperr.InvalidReqError(op, "file_alias", err)
+		}
+
+		if action.Selector != "" {
+			target, _ = s.browser.DescribeElement(ctx, action.Selector)
+		}
+	}
+
+	if target != nil {
+		step.AddEvent("highlighting target")
+
+		if err := s.browser.Highlight(ctx, target.BoundingBox, "DRY-RUN: "+string(action.Type)); err != nil {
+			logger.Warn("Failed to highlight element", zap.Error(err))
+		}
+	}
+
+	logger.Info("Simulated action",
+		zap.String("description", s.formatActionDescription(action)),
+		zap.String("target", describeTarget(target)))
+
+	screenshot, _ = s.takeScreenshot(ctx)
+
+	result = fmt.Sprintf("SIMULATED (dry-run, the page was NOT changed): %s %s on %s.\n"+
+		"Continue planning as if the action succeeded, but expect the page below to be unchanged.\n\n",
+		action.Type, s.formatActionDescription(action), describeTarget(target))
+
+	state, err := s.browser.GetPageState(ctx)
+	if err != nil {
+		return result, screenshot, nil
+	}
+
+	return result + s.optimizePageState(state), screenshot, nil
+}
+
+func describeTarget(elem *entity.Element) string {
+	if elem == nil || elem.Tag == "" {
+		return "unknown element"
+	}
+
+	text := elem.Text
+	if len(text) > 60 {
+		text = text[:60] + "..."
+	}
+
+	if text == "" {
+		return fmt.Sprintf("<%s>", elem.Tag)
+	}
+
+	return fmt.Sprintf("<%s> %q", elem.Tag, text)
+}
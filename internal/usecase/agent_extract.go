@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) actionExtractData(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionExtractData"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, action.Selector))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("selector", action.Selector),
+		attribute.Int("limit", action.Limit))
+	defer func() {
+		step.End(err)
+	}()
+
+	step.AddEvent("extracting data")
+
+	data, err := s.browser.ExtractData(ctx, action.Selector, action.Limit)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "extract_data_failed",
+			apperr.MetaStage:    apperr.StagePageState,
+			apperr.MetaSelector: action.Selector,
+		})
+	}
+
+	if len(data.Rows) == 0 {
+		return "No repeated records (tables, lists or cards) found. Pass the container's element_id or selector, or use read_page.", nil, nil
+	}
+
+	return formatExtractedData(data), nil, nil
+}
+
+// formatExtractedData renders rows as a JSON array that keeps the field
+// order of the page instead of sorting keys like encoding/json does for maps.
+func formatExtractedData(data *entity.ExtractedData) string {
+	var result strings.Builder
+
+	result.WriteString(fmt.Sprintf("Extracted %d of %d records from %s. Fields: %s\n",
+		len(data.Rows), data.Total, data.Source, strings.Join(data.Fields, ", ")))
+
+	result.WriteString("[\n")
+
+	for i, row := range data.Rows {
+		pairs := make([]string, 0, len(row))
+
+		for _, field := range data.Fields {
+			value, ok := row[field]
+			if !ok {
+				continue
+			}
+
+			key, _ := json.Marshal(field)
+			val, _ := json.Marshal(value)
+			pairs = append(pairs, string(key)+": "+string(val))
+		}
+
+		result.WriteString("  {" + strings.Join(pairs, ", ") + "}")
+
+		if i < len(data.Rows)-1 {
+			result.WriteString(",")
+		}
+
+		result.WriteString("\n")
+	}
+
+	result.WriteString("]\n")
+
+	if data.Total > len(data.Rows) {
+		result.WriteString(fmt.Sprintf("%d more records not shown: raise limit or scroll and extract again.\n", data.Total-len(data.Rows)))
+	}
+
+	return result.String()
+}
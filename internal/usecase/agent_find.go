@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) actionFindText(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionFindText"
+	logger := s.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.Bool("exact", action.Exact),
+		attribute.Bool("scroll", action.ScrollIntoView))
+	defer func() {
+		step.End(err)
+	}()
+
+	step.AddEvent("searching text")
+
+	search, err := s.browser.FindText(ctx, action.Value, action.Exact, action.ScrollIntoView)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "find_text_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
+	result = formatTextSearch(search)
+
+	if !search.Scrolled {
+		return result, nil, nil
+	}
+
+	step.AddEvent("getting page state")
+
+	state, err := s.browser.GetPageState(ctx)
+	if err != nil {
+		return result, nil, nil
+	}
+
+	screenshot, _ = s.takeScreenshot(ctx)
+
+	return result + "\nScrolled to the first match.\n\n" + s.optimizePageState(state), screenshot, nil
+}
+
+func formatTextSearch(search *entity.TextSearch) string {
+	if len(search.Matches) == 0 {
+		return fmt.Sprintf("No visible text matching %q on this page.", search.Query)
+	}
+
+	var result strings.Builder
+
+	result.WriteString(fmt.Sprintf("Found %d matches for %q", search.Total, search.Query))
+
+	if search.Total > len(search.Matches) {
+		result.WriteString(fmt.Sprintf(" (showing first %d)", len(search.Matches)))
+	}
+
+	result.WriteString(". Click a match by its id:\n")
+
+	for _, match := range search.Matches {
+		elem := match.Element
+
+		position := "offscreen"
+		if match.InViewport {
+			position = "in view"
+		}
+
+		result.WriteString(fmt.Sprintf("%s [%s] %s | coords: (%.0f,%.0f) %s\n",
+			elementLabel(elem, 0), elem.Tag, truncateText(elem.Text, 120), elem.BoundingBox.X, elem.BoundingBox.Y, position))
+
+		if match.Context != "" && match.Context != elem.Text {
+			result.WriteString(fmt.Sprintf("   context: %s\n", match.Context))
+		}
+	}
+
+	return result.String()
+}
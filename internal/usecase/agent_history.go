@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) actionHistory(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionHistory"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Action, string(action.Type)))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("action_type", string(action.Type)))
+	defer func() {
+		step.End(err)
+	}()
+
+	oldURL := s.lastURL
+
+	var intro string
+
+	switch action.Type {
+	case entity.ActionTypeGoBack:
+		step.AddEvent("going back")
+
+		err = s.browser.GoBack(ctx)
+		intro = "Went back"
+	case entity.ActionTypeGoForward:
+		step.AddEvent("going forward")
+
+		err = s.browser.GoForward(ctx)
+		intro = "Went forward"
+	case entity.ActionTypeReload:
+		step.AddEvent("reloading page")
+
+		err = s.browser.Reload(ctx)
+		intro = "Reloaded the page"
+	default:
+		return "", nil, apperr.WrapErrorWithReason(op, apperr.CodeInvalidArgument, "unknown_action_type")
+	}
+
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: fmt.Sprintf("%s_failed", action.Type),
+			apperr.MetaStage:  apperr.StageNavigation,
+		})
+	}
+
+	step.AddEvent("getting page state")
+
+	state, err := s.browser.GetPageState(ctx)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "page_state_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
+	state, notice, err := s.enforceNavigationPolicy(ctx, state, oldURL)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "page_state_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
+	if action.Type != entity.ActionTypeReload && state.URL == oldURL {
+		intro += " - the URL did not change, there may be no history entry in that direction"
+	}
+
+	s.lastURL = state.URL
+	screenshot, _ = s.takeScreenshot(ctx)
+
+	return fmt.Sprintf("%s.\n\n", intro) + notice + s.optimizePageState(state), screenshot, nil
+}
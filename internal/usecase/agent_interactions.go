@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) actionSelectOption(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionSelectOption"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, action.Selector))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("selector", action.Selector))
+	defer func() {
+		step.End(err)
+	}()
+
+	if action.Selector == "" {
+		return "", nil, apperr.InvalidReqError(op, "selector", fmt.Errorf("selector or element_id is required"))
+	}
+
+	if action.Value == "" {
+		return "", nil, apperr.InvalidReqError(op, "value", fmt.Errorf("option label or value is required"))
+	}
+
+	oldURL := s.lastURL
+
+	step.AddEvent("selecting option")
+
+	selected, err := s.browser.SelectOption(ctx, action.Selector, action.Value)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "select_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: action.Selector,
+		})
+	}
+
+	return s.interactionResult(ctx, op, fmt.Sprintf("Selected: %s.\n\n", selected), oldURL)
+}
+
+func (s *AgentService) actionSetChecked(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionSetChecked"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, action.Selector))
+
+	checked := action.Type == entity.ActionTypeCheck
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("selector", action.Selector),
+		attribute.Bool("checked", checked))
+	defer func() {
+		step.End(err)
+	}()
+
+	if action.Selector == "" {
+		return "", nil, apperr.InvalidReqError(op, "selector", fmt.Errorf("selector or element_id is required"))
+	}
+
+	oldURL := s.lastURL
+
+	step.AddEvent("setting checked state")
+
+	if err := s.browser.SetChecked(ctx, action.Selector, checked); err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   fmt.Sprintf("%s_failed", action.Type),
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: action.Selector,
+		})
+	}
+
+	state := "unchecked"
+	if checked {
+		state = "checked"
+	}
+
+	return s.interactionResult(ctx, op, fmt.Sprintf("The element is now %s.\n\n", state), oldURL)
+}
+
+func (s *AgentService) actionHover(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionHover"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, action.Selector))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("selector", action.Selector))
+	defer func() {
+		step.End(err)
+	}()
+
+	if action.Selector == "" {
+		return "", nil, apperr.InvalidReqError(op, "selector", fmt.Errorf("selector or element_id is required"))
+	}
+
+	oldURL := s.lastURL
+
+	step.AddEvent("hovering element")
+
+	if err := s.browser.Hover(ctx, action.Selector); err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "hover_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: action.Selector,
+		})
+	}
+
+	return s.interactionResult(ctx, op, "Hovering over the element, menus opened on mouseover are listed below.\n\n", oldURL)
+}
+
+func (s *AgentService) actionDrag(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionDrag"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, action.Selector))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("selector", action.Selector),
+		attribute.String("target", action.Target))
+	defer func() {
+		step.End(err)
+	}()
+
+	if action.Selector == "" {
+		return "", nil, apperr.InvalidReqError(op, "selector", fmt.Errorf("selector or element_id is required"))
+	}
+
+	if action.Target == "" && action.X == 0 && action.Y == 0 {
+		return "", nil, apperr.InvalidReqError(op, "target", fmt.Errorf("target_element_id, target_selector or x/y coordinates are required"))
+	}
+
+	oldURL := s.lastURL
+
+	step.AddEvent("dragging element")
+
+	if err := s.browser.Drag(ctx, action.Selector, action.Target, action.X, action.Y); err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "drag_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: action.Selector,
+		})
+	}
+
+	// Drags are usually judged visually (slider position, list order), so
+	// always attach a screenshot.
+	result, screenshot, err = s.interactionResult(ctx, op, "Dragged the element.\n\n", oldURL)
+	if err == nil && screenshot == nil {
+		screenshot, _ = s.takeScreenshot(ctx)
+	}
+
+	return result, screenshot, err
+}
+
+// interactionResult builds the reply for in-page interactions: the page
+// state after the action, with a screenshot when the URL changed.
+func (s *AgentService) interactionResult(ctx context.Context, op, intro, oldURL string) (string, []byte, error) {
+	state, err := s.browser.GetPageState(ctx)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "page_state_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
+	state, notice, err := s.enforceNavigationPolicy(ctx, state, oldURL)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "page_state_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
+	var screenshot []byte
+
+	s.lastURL = state.URL
+
+	if oldURL != state.URL {
+		screenshot, _ = s.takeScreenshot(ctx)
+	}
+
+	return intro + notice + s.optimizePageState(state), screenshot, nil
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) actionReadPage(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionReadPage"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, action.Selector))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("selector", action.Selector),
+		attribute.Int("page", action.Page))
+	defer func() {
+		step.End(err)
+	}()
+
+	step.AddEvent("reading page")
+
+	content, err := s.browser.ReadPage(ctx, action.Selector, action.Page)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "read_page_failed",
+			apperr.MetaStage:    apperr.StagePageState,
+			apperr.MetaSelector: action.Selector,
+		})
+	}
+
+	return formatPageContent(content), nil, nil
+}
+
+func formatPageContent(content *entity.PageContent) string {
+	var result strings.Builder
+
+	result.WriteString(fmt.Sprintf("Content of %s (%s, page %d of %d):\n\n", content.URL, content.Scope, content.Page, content.Pages))
+
+	if strings.TrimSpace(content.Markdown) == "" {
+		result.WriteString("(no readable text found)\n")
+	} else {
+		result.WriteString(content.Markdown)
+		result.WriteString("\n")
+	}
+
+	if content.Page < content.Pages {
+		result.WriteString(fmt.Sprintf("\n--- More content: call read_page with page %d ---\n", content.Page+1))
+	}
+
+	return result.String()
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) actionScrollUntil(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionScrollUntil"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, action.Selector))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("selector", action.Selector),
+		attribute.String("until_selector", action.Target),
+		attribute.Int("count", action.Limit))
+	defer func() {
+		step.End(err)
+	}()
+
+	until := entity.ScrollCondition{
+		Text:     action.Value,
+		Selector: action.Target,
+		Count:    action.Limit,
+	}
+
+	step.AddEvent("scrolling until condition")
+
+	scrollCtx, cancel := s.interruptible(ctx)
+	progress, err := s.browser.ScrollUntil(scrollCtx, action.Selector, until, 0)
+	cancel()
+
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "scroll_until_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: action.Selector,
+		})
+	}
+
+	step.AddEvent("getting page state")
+
+	state, err := s.browser.GetPageState(ctx)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "page_state_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
+	screenshot, _ = s.takeScreenshot(ctx)
+
+	return formatScrollProgress(progress, until) + s.optimizePageState(state), screenshot, nil
+}
+
+func formatScrollResult(scrolled *entity.ScrollResult) string {
+	if scrolled == nil {
+		return ""
+	}
+
+	if !scrolled.Moved {
+		edge := "cannot scroll further"
+
+		switch {
+		case scrolled.AtBottom && scrolled.AtTop:
+			edge = "is not scrollable"
+		case scrolled.AtBottom:
+			edge = "is already at the bottom"
+		case scrolled.AtTop:
+			edge = "is already at the top"
+		}
+
+		return fmt.Sprintf("Nothing moved: %s %s. Pass the element_id of the pane to scroll, or use scroll_until for feeds that load more.\n\n", scrolled.Container, edge)
+	}
+
+	return fmt.Sprintf("Scrolled %s, now at %d%%.\n\n", scrolled.Container, scrolled.Progress)
+}
+
+func formatScrollProgress(progress *entity.ScrollProgress, until entity.ScrollCondition) string {
+	var result strings.Builder
+
+	result.WriteString(fmt.Sprintf("Scrolled %s %d times, stopped: %s.", progress.Container, progress.Rounds, progress.Stopped))
+
+	if until.Selector != "" {
+		result.WriteString(fmt.Sprintf(" %d elements match %s.", progress.Items, until.Selector))
+	} else if progress.Items > 0 {
+		result.WriteString(fmt.Sprintf(" The list holds about %d items.", progress.Items))
+	}
+
+	if until.Text != "" && !progress.Met {
+		result.WriteString(fmt.Sprintf(" %q was not found.", until.Text))
+	}
+
+	if progress.Match != nil {
+		result.WriteString(fmt.Sprintf("\nFound %q, scrolled into view: %s [%s] %s | coords: (%.0f,%.0f)",
+			until.Text, elementLabel(*progress.Match, 0), progress.Match.Tag, truncateText(progress.Match.Text, 120),
+			progress.Match.BoundingBox.X, progress.Match.BoundingBox.Y))
+	}
+
+	result.WriteString("\n\n")
+
+	return result.String()
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) actionListTabs(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionListTabs"
+	logger := s.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op)
+	defer func() {
+		step.End(err)
+	}()
+
+	tabs, err := s.browser.ListTabs(ctx)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "list_tabs_failed",
+			apperr.MetaStage:  apperr.StageBrowser,
+		})
+	}
+
+	step.AddEvent("tabs listed", attribute.Int("tabs_count", len(tabs)))
+
+	return formatTabs(tabs), nil, nil
+}
+
+func (s *AgentService) actionSwitchTab(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionSwitchTab"
+	logger := s.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.Int("tab_id", action.TabID))
+	defer func() {
+		step.End(err)
+	}()
+
+	if err := s.browser.SwitchTab(ctx, action.TabID); err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "switch_tab_failed",
+			apperr.MetaStage:  apperr.StageBrowser,
+		})
+	}
+
+	return s.activeTabState(ctx, op, fmt.Sprintf("Switched to tab %d.\n\n", action.TabID))
+}
+
+func (s *AgentService) actionCloseTab(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionCloseTab"
+	logger := s.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.Int("tab_id", action.TabID))
+	defer func() {
+		step.End(err)
+	}()
+
+	if err := s.browser.CloseTab(ctx, action.TabID); err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "close_tab_failed",
+			apperr.MetaStage:  apperr.StageBrowser,
+		})
+	}
+
+	return s.activeTabState(ctx, op, fmt.Sprintf("Closed tab %d.\n\n", action.TabID))
+}
+
+func (s *AgentService) actionNewTab(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionNewTab"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.URL, action.URL))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("url", action.URL))
+	defer func() {
+		step.End(err)
+	}()
+
+	if action.URL != "" {
+		if allowed, reason := s.urlFilter.Check(action.URL); !allowed {
+			step.AddEvent("navigation blocked by policy")
+
+			return "", nil, apperr.Wrap(op, apperr.CodePolicyDenied, fmt.Errorf("navigation to %s is blocked: %s", action.URL, reason), map[string]any{
+				apperr.MetaReason: "navigation_denied",
+				apperr.MetaStage:  apperr.StageNavigation,
+				apperr.MetaURL:    action.URL,
+			})
+		}
+	}
+
+	id, err := s.browser.NewTab(ctx, action.URL)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason: "new_tab_failed",
+			apperr.MetaStage:  apperr.StageBrowser,
+			apperr.MetaURL:    action.URL,
+		})
+	}
+
+	return s.activeTabState(ctx, op, fmt.Sprintf("Opened tab %d.\n\n", id))
+}
+
+func (s *AgentService) activeTabState(ctx context.Context, op, intro string) (string, []byte, error) {
+	state, err := s.browser.GetPageState(ctx)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "page_state_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
+	// A popup the site opened, or a form the upload submitted, may sit on
+	// a blocked URL.
+	state, notice, err := s.enforceNavigationPolicy(ctx, state, s.lastURL)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeInternal, err, map[string]any{
+			apperr.MetaReason: "page_state_failed",
+			apperr.MetaStage:  apperr.StagePageState,
+		})
+	}
+
+	s.lastURL = state.URL
+	s.lastState = nil
+	screenshot, _ := s.takeScreenshot(ctx)
+
+	return intro + notice + s.optimizePageState(state), screenshot, nil
+}
+
+// newTabsNotice tells the model about tabs the page opened on its own, such
+// as target=_blank links or login popups, which would otherwise go unnoticed.
+func (s *AgentService) newTabsNotice() string {
+	tabs := s.browser.DrainNewTabs()
+	if len(tabs) == 0 {
+		return ""
+	}
+
+	var notice strings.Builder
+
+	for _, tab := range tabs {
+		notice.WriteString(fmt.Sprintf("🗂 New tab opened: [%d] %s (%s). Use switch_tab(%d) to work in it.\n",
+			tab.ID, tab.Title, tab.URL, tab.ID))
+	}
+
+	return notice.String()
+}
+
+func formatTabs(tabs []entity.Tab) string {
+	if len(tabs) == 0 {
+		return "No open tabs"
+	}
+
+	var result strings.Builder
+
+	result.WriteString("Open tabs:\n")
+
+	for _, tab := range tabs {
+		marker := " "
+		if tab.Active {
+			marker = "*"
+		}
+
+		result.WriteString(fmt.Sprintf("%s [%d] %s (%s)\n", marker, tab.ID, tab.Title, tab.URL))
+	}
+
+	return result.String()
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) Pause() {
+	s.logger.Info("Pause requested")
+	s.pauseRequested.Store(true)
+}
+
+func (s *AgentService) Resume(note string) {
+	if !s.pauseRequested.Load() {
+		fmt.Println("The agent is not paused")
+
+		return
+	}
+
+	select {
+	case s.resumeChan <- note:
+	default:
+	}
+}
+
+const pausePollInterval = 200 * time.Millisecond
+
+// interruptible returns a context that is also cancelled when the task is
+// stopped or a pause is requested, for browser loops that run for a while.
+func (s *AgentService) interruptible(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := s.stopSignal()
+
+	go func() {
+		ticker := time.NewTicker(pausePollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stop:
+				cancel()
+
+				return
+			case <-ticker.C:
+				if s.pauseRequested.Load() {
+					cancel()
+
+					return
+				}
+			}
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// waitForResume blocks the loop while the human operates the browser and
+// returns a message describing what changed in the meantime.
+func (s *AgentService) waitForResume(ctx context.Context) (msg entity.AIMessage, err error) {
+	const op = "waitForResume"
+	logger := s.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op)
+	defer func() {
+		step.End(err)
+	}()
+
+	defer s.pauseRequested.Store(false)
+
+	before, _ := s.browser.GetPageState(ctx)
+
+	fmt.Println("\n⏸  Agent paused. Use the browser, then type 'resume [note for the agent]'.")
+	step.AddEvent("paused")
+
+	var note string
+
+	select {
+	case note = <-s.resumeChan:
+	case <-ctx.Done():
+		return msg, ctx.Err()
+	case <-s.stopSignal():
+		return msg, apperr.WrapErrorWithReason(op, apperr.CodeCancelledByUser, "stopped_while_paused")
+	}
+
+	step.AddEvent("resumed")
+	fmt.Println("▶️  Resuming")
+
+	text, screenshot := s.handBackSummary(ctx, before, "The user paused you and operated the browser manually.", note)
+
+	return s.createMessageWithScreenshot("user", s.forModel(text), screenshot), nil
+}
+
+func (s *AgentService) actionAskUser(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionAskUser"
+	logger := s.logger.With(zap.String(logg.Operation, op))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("question", action.Value))
+	defer func() {
+		step.End(err)
+	}()
+
+	if action.Value == "" {
+		return "", nil, apperr.InvalidReqError(op, "question", fmt.Errorf("question cannot be empty"))
+	}
+
+	before, _ := s.browser.GetPageState(ctx)
+
+	fmt.Printf("\n🙋 The agent needs your help: %s\n", action.Value)
+	fmt.Println("   You can use the browser now. Type an answer (or just press Enter) when done.")
+
+	step.AddEvent("waiting for user")
+
+	answer, err := s.operator.Ask(ctx, "Answer: ")
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeCancelledByUser, err, map[string]any{
+			apperr.MetaReason: "no_answer",
+		})
+	}
+
+	step.AddEvent("user answered")
+
+	result, screenshot = s.handBackSummary(ctx, before, "You asked the user: "+action.Value, answer)
+
+	return result, screenshot, nil
+}
+
+func (s *AgentService) handBackSummary(ctx context.Context, before *entity.PageState, intro, note string) (string, []byte) {
+	var text strings.Builder
+
+	text.WriteString(intro)
+	text.WriteString("\n")
+
+	if note != "" {
+		text.WriteString(fmt.Sprintf("User says: %s\n", note))
+	} else {
+		text.WriteString("The user gave no text answer.\n")
+	}
+
+	after, err := s.browser.GetPageState(ctx)
+	if err != nil {
+		text.WriteString("Could not read the page after the hand-back.\n")
+
+		return text.String(), nil
+	}
+
+	s.lastURL = after.URL
+
+	if before != nil {
+		switch {
+		case before.URL != after.URL:
+			text.WriteString(fmt.Sprintf("The page changed from %s to %s.\n", before.URL, after.URL))
+		case pageFingerprint(before) != pageFingerprint(after):
+			text.WriteString("The URL is the same but the page content changed.\n")
+		default:
+			text.WriteString("The page looks unchanged.\n")
+		}
+	}
+
+	text.WriteString("Control is back with you. Current page:\n\n")
+	text.WriteString(s.optimizePageState(after))
+
+	screenshot, _ := s.takeScreenshot(ctx)
+
+	return text.String(), screenshot
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// keyTokenPattern matches inline keys in typed text, e.g. {Enter} or
+// {Control+A}. Secret placeholders ({{secret:name}}) never match.
+var keyTokenPattern = regexp.MustCompile(`\{([A-Za-z0-9]+(?:\+[A-Za-z0-9]+)*)\}`)
+
+func (s *AgentService) actionTypeText(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionTypeText"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, action.Selector))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("selector", action.Selector),
+		attribute.Int("delay", action.Delay),
+		attribute.Bool("clear", action.Clear))
+	defer func() {
+		step.End(err)
+	}()
+
+	if action.Value == "" {
+		return "", nil, apperr.InvalidReqError(op, "text", fmt.Errorf("text cannot be empty"))
+	}
+
+	segments, err := s.typingSegments(action.Value)
+	if err != nil {
+		return "", nil, apperr.InvalidReqError(op, "text", err)
+	}
+
+	oldURL := s.lastURL
+
+	step.AddEvent("typing text", attribute.Int("segments", len(segments)))
+
+	typed, err := s.browser.TypeText(ctx, action.Selector, segments, action.Delay, action.Clear)
+	if err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "type_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: action.Selector,
+		})
+	}
+
+	return s.interactionResult(ctx, op, "Typed the text. "+s.formatTypingResult(typed)+"\n", oldURL)
+}
+
+// typingSegments splits text on inline keys before secrets are substituted,
+// so a secret value is always typed literally and never pressed as keys.
+func (s *AgentService) typingSegments(text string) ([]entity.TypingSegment, error) {
+	var segments []entity.TypingSegment
+
+	addText := func(part string) error {
+		if part == "" {
+			return nil
+		}
+
+		if s.secrets.HasPlaceholder(part) {
+			resolved, err := s.secrets.Resolve(part)
+			if err != nil {
+				return err
+			}
+
+			part = resolved
+		}
+
+		segments = append(segments, entity.TypingSegment{Text: part})
+
+		return nil
+	}
+
+	last := 0
+	for _, match := range keyTokenPattern.FindAllStringSubmatchIndex(text, -1) {
+		if err := addText(text[last:match[0]]); err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, entity.TypingSegment{Key: text[match[2]:match[3]]})
+		last = match[1]
+	}
+
+	if err := addText(text[last:]); err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+// formatTypingResult reports what the field ended up containing, which
+// matters for masked inputs, and lists autocomplete suggestions by id.
+func (s *AgentService) formatTypingResult(typed *entity.TypingResult) string {
+	if typed == nil {
+		return ""
+	}
+
+	var result strings.Builder
+
+	if typed.Value != "" {
+		result.WriteString(fmt.Sprintf("Field value: %q\n", truncateText(s.secrets.Mask(typed.Value), 200)))
+	}
+
+	if len(typed.Suggestions) == 0 {
+		result.WriteString("No autocomplete suggestions appeared.\n")
+
+		return result.String()
+	}
+
+	result.WriteString("Autocomplete suggestions (click one by its id to pick it):\n")
+
+	for i, elem := range typed.Suggestions {
+		if elem.ID == 0 {
+			result.WriteString(fmt.Sprintf("%d. %s (browser datalist, type it or pick with ArrowDown+Enter)\n", i+1, truncateText(elem.Text, 120)))
+
+			continue
+		}
+
+		result.WriteString(fmt.Sprintf("%s [%s] %s | coords: (%.0f,%.0f)\n",
+			elementLabel(elem, i+1), elem.Tag, truncateText(elem.Text, 120), elem.BoundingBox.X, elem.BoundingBox.Y))
+	}
+
+	return result.String()
+}
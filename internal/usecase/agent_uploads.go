@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"ai-agent-task/pkg/apperr"
+	"ai-agent-task/pkg/logg"
+	"ai-agent-task/pkg/tracing"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func (s *AgentService) actionUploadFile(ctx context.Context, action *entity.BrowserAction) (result string, screenshot []byte, err error) {
+	const op = "actionUploadFile"
+	logger := s.logger.With(zap.String(logg.Operation, op), zap.String(logg.Selector, action.Selector))
+
+	ctx, step := tracing.StartSpan(ctx, s.tracer, logger, op,
+		attribute.String("selector", action.Selector),
+		attribute.String("file_alias", action.Value))
+	defer func() {
+		step.End(err)
+	}()
+
+	path, err := s.files.Resolve(action.Value)
+	if err != nil {
+		available := "none"
+		if aliases := s.files.Aliases(); len(aliases) > 0 {
+			available = strings.Join(aliases, ", ")
+		}
+
+		return "", nil, apperr.InvalidReqError(op, "file_alias", fmt.Errorf("%w, available files: %s", err, available))
+	}
+
+	step.AddEvent("uploading file")
+
+	if err := s.browser.UploadFile(ctx, action.Selector, []string{path}); err != nil {
+		return "", nil, apperr.Wrap(op, apperr.CodeActionFailed, err, map[string]any{
+			apperr.MetaReason:   "upload_failed",
+			apperr.MetaStage:    apperr.StageInteraction,
+			apperr.MetaSelector: action.Selector,
+		})
+	}
+
+	return s.activeTabState(ctx, op, fmt.Sprintf("Attached file %s. Check the page shows it before submitting.\n\n", action.Value))
+}
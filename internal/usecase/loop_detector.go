@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+)
+
+const (
+	loopKindRepeat      = "repeat"
+	loopKindOscillation = "oscillation"
+
+	coordinateBucket = 10.0
+)
+
+type actionRecord struct {
+	key         string
+	fingerprint string
+	action      *entity.BrowserAction
+}
+
+type loopDetection struct {
+	Kind    string
+	Message string
+}
+
+type loopDetector struct {
+	window  int
+	history []actionRecord
+	stuck   int
+}
+
+func newLoopDetector(window int) *loopDetector {
+	if window < 4 {
+		window = 4
+	}
+
+	return &loopDetector{
+		window:  window,
+		history: make([]actionRecord, 0, window),
+	}
+}
+
+func (d *loopDetector) Check(action *entity.BrowserAction, fingerprint string) *loopDetection {
+	key := actionKey(action)
+
+	// A cycle needs the page states to recur along with the actions, so
+	// paging through results (click Next, extract, click Next...) on
+	// changing pages is not a loop.
+	steps := make([]string, 0, len(d.history)+1)
+	for _, rec := range d.history {
+		steps = append(steps, rec.key+"\x00"+rec.fingerprint)
+	}
+	steps = append(steps, key+"\x00"+fingerprint)
+
+	for period := 2; period <= 3; period++ {
+		if !isCycle(steps, period) {
+			continue
+		}
+
+		cycle := make([]*entity.BrowserAction, 0, period)
+		for _, rec := range d.history[len(d.history)-period+1:] {
+			cycle = append(cycle, rec.action)
+		}
+		cycle = append(cycle, action)
+
+		return &loopDetection{
+			Kind:    loopKindOscillation,
+			Message: oscillationMessage(cycle),
+		}
+	}
+
+	for _, rec := range d.history {
+		if rec.key == key && rec.fingerprint == fingerprint {
+			return &loopDetection{
+				Kind: loopKindRepeat,
+				Message: fmt.Sprintf("You already did '%s' on this exact page state and nothing changed. "+
+					"Do not repeat it. Pick a different element, check the screenshot for overlays or errors, "+
+					"scroll to reveal other content, or navigate elsewhere.", describeLoopAction(action)),
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *loopDetector) Record(action *entity.BrowserAction, fingerprint string) {
+	d.history = append(d.history, actionRecord{
+		key:         actionKey(action),
+		fingerprint: fingerprint,
+		action:      action,
+	})
+
+	if len(d.history) > d.window {
+		d.history = d.history[len(d.history)-d.window:]
+	}
+}
+
+func (d *loopDetector) MarkStuck() int {
+	d.stuck++
+
+	return d.stuck
+}
+
+// isCycle reports whether the last step returns to the step period positions
+// back, with at least two different actions in between: the agent went
+// somewhere and came back to the same action on the same page.
+func isCycle(steps []string, period int) bool {
+	if len(steps) < period+1 {
+		return false
+	}
+
+	last := len(steps) - 1
+	if steps[last] != steps[last-period] {
+		return false
+	}
+
+	distinct := make(map[string]struct{}, period)
+	for _, step := range steps[last-period : last] {
+		distinct[step[:strings.IndexByte(step, 0)]] = struct{}{}
+	}
+
+	return len(distinct) >= 2
+}
+
+func oscillationMessage(cycle []*entity.BrowserAction) string {
+	description := ""
+
+	for i, a := range cycle {
+		if i > 0 {
+			description += " -> "
+		}
+
+		description += describeLoopAction(a)
+	}
+
+	scrollOnly := true
+
+	for _, a := range cycle {
+		if a.Type != entity.ActionTypeScroll {
+			scrollOnly = false
+
+			break
+		}
+	}
+
+	if scrollOnly {
+		return fmt.Sprintf("You are scrolling back and forth (%s) without progress. "+
+			"The content you need is not revealed by scrolling; click a visible element, "+
+			"use a search field or navigate to a more specific page instead.", description)
+	}
+
+	return fmt.Sprintf("You are cycling between the same actions (%s) and the page keeps returning to the same state. "+
+		"Break the cycle: choose an action you have not tried yet, or complete the task with what you already found.", description)
+}
+
+func describeLoopAction(action *entity.BrowserAction) string {
+	switch action.Type {
+	case entity.ActionTypeNavigate:
+		return fmt.Sprintf("navigate %s", action.URL)
+	case entity.ActionTypeClick:
+		return fmt.Sprintf("click %s", action.Selector)
+	case entity.ActionTypeFill:
+		return fmt.Sprintf("fill %s", action.Selector)
+	case entity.ActionTypePress:
+		return fmt.Sprintf("press %s", action.Value)
+	case entity.ActionTypeScroll:
+		return fmt.Sprintf("scroll %s", action.Value)
+	case entity.ActionTypeClickCoordinates:
+		return fmt.Sprintf("click_at_coordinates %.0f,%.0f", action.X, action.Y)
+	case entity.ActionTypeSwitchTab, entity.ActionTypeCloseTab:
+		return fmt.Sprintf("%s %d", action.Type, action.TabID)
+	default:
+		return string(action.Type)
+	}
+}
+
+func actionKey(action *entity.BrowserAction) string {
+	switch action.Type {
+	case entity.ActionTypeNavigate:
+		return fmt.Sprintf("%s|%s", action.Type, action.URL)
+	case entity.ActionTypeClick:
+		return fmt.Sprintf("%s|%s", action.Type, action.Selector)
+	case entity.ActionTypeFill:
+		return fmt.Sprintf("%s|%s|%s", action.Type, action.Selector, action.Value)
+	case entity.ActionTypeScroll:
+		return fmt.Sprintf("%s|%s|%s|%d", action.Type, action.Selector, action.Value, action.WaitFor)
+	case entity.ActionTypeScrollUntil:
+		return fmt.Sprintf("%s|%s|%s|%s|%d", action.Type, action.Selector, action.Value, action.Target, action.Limit)
+	case entity.ActionTypeClickCoordinates:
+		return fmt.Sprintf("%s|%.0f|%.0f", action.Type,
+			math.Round(action.X/coordinateBucket), math.Round(action.Y/coordinateBucket))
+	case entity.ActionTypeSwitchTab, entity.ActionTypeCloseTab:
+		return fmt.Sprintf("%s|%d", action.Type, action.TabID)
+	case entity.ActionTypeReadPage:
+		return fmt.Sprintf("%s|%s|%d", action.Type, action.Selector, action.Page)
+	case entity.ActionTypeExtractData:
+		return fmt.Sprintf("%s|%s|%d", action.Type, action.Selector, action.Limit)
+	case entity.ActionTypeFindText:
+		return fmt.Sprintf("%s|%s|%t|%t", action.Type, action.Value, action.Exact, action.ScrollIntoView)
+	case entity.ActionTypeTypeText:
+		return fmt.Sprintf("%s|%s|%s|%t", action.Type, action.Selector, action.Value, action.Clear)
+	case entity.ActionTypeDrag:
+		return fmt.Sprintf("%s|%s|%s|%.0f|%.0f", action.Type, action.Selector, action.Target, action.X, action.Y)
+	default:
+		return fmt.Sprintf("%s|%s|%s|%s", action.Type, action.Selector, action.Value, action.URL)
+	}
+}
+
+func pageFingerprint(state *entity.PageState) string {
+	if state == nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+
+	for _, elem := range state.Elements {
+		h.Write([]byte(elem.Tag))
+		h.Write([]byte{0})
+		h.Write([]byte(elem.Text))
+		h.Write([]byte{0})
+		h.Write([]byte(elem.Selector))
+		h.Write([]byte{0})
+	}
+
+	for _, node := range state.AXNodes {
+		h.Write([]byte(node.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(node.Name))
+		h.Write([]byte{0})
+		h.Write([]byte(node.Value))
+		h.Write([]byte{0})
+		h.Write([]byte(strings.Join(node.States, ",")))
+		h.Write([]byte{0})
+	}
+
+	return fmt.Sprintf("%s#%x", state.URL, h.Sum64())
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"testing"
+)
+
+type loopStep struct {
+	action      *entity.BrowserAction
+	fingerprint string
+}
+
+func click(selector string) *entity.BrowserAction {
+	return &entity.BrowserAction{Type: entity.ActionTypeClick, Selector: selector}
+}
+
+func TestLoopDetectorCheck(t *testing.T) {
+	scroll := func(direction string) *entity.BrowserAction {
+		return &entity.BrowserAction{Type: entity.ActionTypeScroll, Value: direction}
+	}
+
+	tests := []struct {
+		name    string
+		history []loopStep
+		next    loopStep
+		want    string
+	}{
+		{name: "fresh action", history: []loopStep{{click("#a"), "p1"}}, next: loopStep{click("#b"), "p1"}},
+		{name: "same action on the same page", history: []loopStep{{click("#a"), "p1"}}, next: loopStep{click("#a"), "p1"}, want: loopKindRepeat},
+		{name: "same action on a changed page", history: []loopStep{{click("#a"), "p1"}}, next: loopStep{click("#a"), "p2"}},
+		{
+			name:    "two step cycle",
+			history: []loopStep{{click("#tab1"), "p1"}, {click("#tab2"), "p2"}},
+			next:    loopStep{click("#tab1"), "p1"},
+			want:    loopKindOscillation,
+		},
+		{
+			name:    "three step cycle",
+			history: []loopStep{{click("#a"), "p1"}, {click("#b"), "p2"}, {click("#c"), "p3"}},
+			next:    loopStep{click("#a"), "p1"},
+			want:    loopKindOscillation,
+		},
+		{
+			name:    "paging through changing results",
+			history: []loopStep{{click("#next"), "page1"}, {click("#extract"), "page2"}},
+			next:    loopStep{click("#next"), "page2"},
+		},
+		{
+			name:    "scrolling back and forth",
+			history: []loopStep{{scroll("down"), "top"}, {scroll("up"), "bottom"}},
+			next:    loopStep{scroll("down"), "top"},
+			want:    loopKindOscillation,
+		},
+		{
+			name:    "coordinates in the same bucket",
+			history: []loopStep{{&entity.BrowserAction{Type: entity.ActionTypeClickCoordinates, X: 101, Y: 198}, "p1"}},
+			next:    loopStep{&entity.BrowserAction{Type: entity.ActionTypeClickCoordinates, X: 99, Y: 202}, "p1"},
+			want:    loopKindRepeat,
+		},
+		{
+			name:    "fill with a new value",
+			history: []loopStep{{&entity.BrowserAction{Type: entity.ActionTypeFill, Selector: "#q", Value: "a"}, "p1"}},
+			next:    loopStep{&entity.BrowserAction{Type: entity.ActionTypeFill, Selector: "#q", Value: "b"}, "p1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newLoopDetector(8)
+			for _, step := range tt.history {
+				d.Record(step.action, step.fingerprint)
+			}
+
+			got := d.Check(tt.next.action, tt.next.fingerprint)
+
+			switch {
+			case tt.want == "" && got != nil:
+				t.Fatalf("Check() = %s (%s), want no loop", got.Kind, got.Message)
+			case tt.want != "" && got == nil:
+				t.Fatalf("Check() = nil, want %s", tt.want)
+			case got != nil && got.Kind != tt.want:
+				t.Fatalf("Check() = %s, want %s", got.Kind, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoopDetectorWindow(t *testing.T) {
+	d := newLoopDetector(4)
+
+	d.Record(click("#old"), "p1")
+	for _, selector := range []string{"#a", "#b", "#c", "#d"} {
+		d.Record(click(selector), "p"+selector)
+	}
+
+	if got := d.Check(click("#old"), "p1"); got != nil {
+		t.Fatalf("Check() = %s, want the action outside the window to be forgotten", got.Kind)
+	}
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	maxListedClickable = 40
+	maxListedOther     = 10
+)
+
+func listedElements(elements []entity.Element) (clickable, other []entity.Element) {
+	for _, elem := range elements {
+		if elem.Clickable {
+			if len(clickable) < maxListedClickable {
+				clickable = append(clickable, elem)
+			}
+		} else if elem.Text != "" && len(elem.Text) >= 3 {
+			if len(other) < maxListedOther {
+				other = append(other, elem)
+			}
+		}
+	}
+
+	return clickable, other
+}
+
+func elementKey(elem entity.Element) string {
+	if elem.ID > 0 {
+		return fmt.Sprintf("#%d", elem.ID)
+	}
+
+	return elem.Tag + "|" + elem.Selector
+}
+
+// diffPageState describes what changed since the state the model saw last.
+// It reports false when a full listing is more useful: first step, another
+// URL, or so many changes (e.g. after scrolling) that a delta saves nothing.
+func diffPageState(previous, current *entity.PageState) (string, bool) {
+	if previous == nil || len(previous.Elements) == 0 || len(previous.AXNodes) > 0 {
+		return "", false
+	}
+
+	if previous.URL != current.URL {
+		return "", false
+	}
+
+	prevClickable, prevOther := listedElements(previous.Elements)
+	curClickable, curOther := listedElements(current.Elements)
+
+	prevListed := append(prevClickable, prevOther...)
+	listed := append(curClickable, curOther...)
+
+	before := make(map[string]entity.Element, len(prevListed))
+	for _, elem := range prevListed {
+		before[elementKey(elem)] = elem
+	}
+
+	var added, changed, moved, removed []entity.Element
+	var modals []entity.Element
+
+	seen := make(map[string]bool, len(listed))
+
+	for _, elem := range listed {
+		key := elementKey(elem)
+		seen[key] = true
+
+		old, ok := before[key]
+		if !ok {
+			added = append(added, elem)
+
+			if isModalElement(elem) {
+				modals = append(modals, elem)
+			}
+
+			continue
+		}
+
+		if old.Text != elem.Text {
+			changed = append(changed, elem)
+		} else if elem.Clickable && boxMoved(old.BoundingBox, elem.BoundingBox) {
+			moved = append(moved, elem)
+		}
+	}
+
+	for _, elem := range prevListed {
+		if !seen[elementKey(elem)] {
+			removed = append(removed, elem)
+		}
+	}
+
+	if len(added)+len(changed)+len(moved)+len(removed) > len(listed)/2 {
+		return "", false
+	}
+
+	var result strings.Builder
+
+	result.WriteString("Page changes since the last step (same URL, unchanged elements omitted):\n")
+
+	if previous.Title != current.Title {
+		result.WriteString(fmt.Sprintf("Title changed from %q\n", previous.Title))
+	}
+
+	for _, elem := range modals {
+		result.WriteString(fmt.Sprintf("⚠️ Modal appeared: %s %s\n", elementLabel(elem, 0), truncateText(elem.Text, 100)))
+	}
+
+	if len(added)+len(changed)+len(moved)+len(removed) == 0 {
+		if previous.Title == current.Title {
+			result.WriteString("No visible changes - the last action may have had no effect.\n")
+		}
+
+		return result.String(), true
+	}
+
+	if len(added) > 0 {
+		result.WriteString("New elements:\n")
+
+		for _, elem := range added {
+			result.WriteString(formatDeltaElement(elem))
+		}
+	}
+
+	if len(changed) > 0 {
+		result.WriteString("Changed text:\n")
+
+		for _, elem := range changed {
+			old := before[elementKey(elem)]
+			result.WriteString(fmt.Sprintf("%s [%s] %q -> %q\n",
+				elementLabel(elem, 0), elem.Tag, truncateText(old.Text, 80), truncateText(elem.Text, 80)))
+		}
+	}
+
+	if len(moved) > 0 {
+		result.WriteString("Moved:\n")
+
+		for _, elem := range moved {
+			result.WriteString(fmt.Sprintf("%s coords: (%.0f,%.0f)\n", elementLabel(elem, 0), elem.BoundingBox.X, elem.BoundingBox.Y))
+		}
+	}
+
+	if len(removed) > 0 {
+		labels := make([]string, 0, len(removed))
+
+		for _, elem := range removed {
+			labels = append(labels, fmt.Sprintf("%s %s", elementLabel(elem, 0), truncateText(elem.Text, 40)))
+		}
+
+		result.WriteString("Removed: " + strings.Join(labels, ", ") + "\n")
+	}
+
+	return result.String(), true
+}
+
+func formatDeltaElement(elem entity.Element) string {
+	line := fmt.Sprintf("%s [%s] %s", elementLabel(elem, 0), elem.Tag, truncateText(elem.Text, 200))
+
+	if elem.Clickable {
+		if len(elem.Selector) <= 100 {
+			line += " | selector: " + elem.Selector
+		}
+
+		line += fmt.Sprintf(" | coords: (%.0f,%.0f) size: %.0fx%.0f",
+			elem.BoundingBox.X, elem.BoundingBox.Y, elem.BoundingBox.Width, elem.BoundingBox.Height)
+	}
+
+	return line + "\n"
+}
+
+func isModalElement(elem entity.Element) bool {
+	switch elem.Attributes["role"] {
+	case "dialog", "alertdialog":
+		return true
+	default:
+		return false
+	}
+}
+
+func boxMoved(a, b entity.BoundingBox) bool {
+	return math.Abs(a.X-b.X) > 2 || math.Abs(a.Y-b.Y) > 2
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"ai-agent-task/internal/entity"
+	"fmt"
+	"strings"
+)
+
+const maxAXDepth = 12
+
+// formatAccessibilityTree renders the accessibility page state as an indented
+// outline. Interactive nodes carry a role+name selector for click and fill.
+func formatAccessibilityTree(nodes []entity.AXNode) string {
+	var result strings.Builder
+
+	result.WriteString("Accessibility tree (use the role=... selectors with click/fill):\n")
+
+	for _, node := range nodes {
+		depth := node.Depth
+		if depth > maxAXDepth {
+			depth = maxAXDepth
+		}
+
+		result.WriteString(strings.Repeat("  ", depth))
+		result.WriteString(node.Role)
+
+		if node.Name != "" {
+			result.WriteString(fmt.Sprintf(" %q", node.Name))
+		}
+
+		if node.Value != "" && node.Value != node.Name {
+			result.WriteString(fmt.Sprintf(" value=%q", node.Value))
+		}
+
+		if len(node.States) > 0 {
+			result.WriteString(" [" + strings.Join(node.States, ", ") + "]")
+		}
+
+		if node.Selector != "" {
+			result.WriteString(" | selector: " + node.Selector)
+		}
+
+		if node.BoundingBox.Width > 0 {
+			result.WriteString(fmt.Sprintf(" | coords: (%.0f,%.0f)", node.BoundingBox.X, node.BoundingBox.Y))
+		}
+
+		result.WriteString("\n")
+	}
+
+	return result.String()
+}
@@ -2,8 +2,12 @@ package usecase
 
 import (
 	"ai-agent-task/internal/config"
+	"ai-agent-task/internal/files"
+	"ai-agent-task/internal/policy"
 	"ai-agent-task/internal/ports"
+	"ai-agent-task/internal/secrets"
 	"ai-agent-task/internal/usecase/adapters"
+	"ai-agent-task/pkg/redact"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -18,10 +22,16 @@ type Service struct {
 type Params struct {
 	fx.In
 
-	Logger  *zap.Logger
-	Config  *config.Config
-	Browser ports.BrowserManager
-	AI      ports.AIClient
+	Logger    *zap.Logger
+	Config    *config.Config
+	Browser   ports.BrowserManager
+	AI        ports.AIClient
+	Operator  ports.Operator
+	Policy    *policy.Engine
+	URLFilter *policy.URLFilter
+	Secrets   *secrets.Vault
+	Files     *files.Library
+	Redactor  *redact.Redactor
 }
 
 func NewUsecase(params Params) *Service {
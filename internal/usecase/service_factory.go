@@ -16,10 +16,16 @@ func newServiceFactory(deps Params) *serviceFactory {
 
 func (f *serviceFactory) CreateAgentService() adapters.AgentService {
 	return NewAgentService(AgentServiceParams{
-		Browser: f.deps.Browser,
-		AI:      f.deps.AI,
-		Config:  f.deps.Config,
-		Logger:  f.deps.Logger,
+		Browser:   f.deps.Browser,
+		AI:        f.deps.AI,
+		Operator:  f.deps.Operator,
+		Config:    f.deps.Config,
+		Logger:    f.deps.Logger,
+		Policy:    f.deps.Policy,
+		URLFilter: f.deps.URLFilter,
+		Secrets:   f.deps.Secrets,
+		Files:     f.deps.Files,
+		Redactor:  f.deps.Redactor,
 	})
 }
 
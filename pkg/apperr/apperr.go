@@ -1,6 +1,9 @@
 package apperr
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 const (
 	MetaReason   = "reason"
@@ -32,6 +35,10 @@ const (
 	CodeBrowserNotReady   = "browser_not_ready"
 	CodeActionFailed      = "action_failed"
 	CodeAIError           = "ai_error"
+	CodeInvalidResult     = "invalid_result"
+	CodeStuck             = "stuck"
+	CodePolicyDenied      = "policy_denied"
+	CodeStaleElement      = "stale_element"
 )
 
 type Error struct {
@@ -73,7 +80,7 @@ func WrapWithReason(op, code string, err error, reason string) error {
 }
 
 func WrapErrorWithReason(op, code, reason string) error {
-	return Wrap(op, code, fmt.Errorf(reason), map[string]any{
+	return Wrap(op, code, errors.New(reason), map[string]any{
 		MetaReason: reason,
 	})
 }
@@ -0,0 +1,335 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Supported keywords: type, properties, required, additionalProperties,
+// items, enum, minItems, maxItems, minLength, maxLength, minimum, maximum.
+// Parse rejects any other keyword, so a schema never silently validates less
+// than it says.
+type Schema = map[string]interface{}
+
+var supportedKeywords = map[string]bool{
+	"type":                 true,
+	"properties":           true,
+	"required":             true,
+	"additionalProperties": true,
+	"items":                true,
+	"enum":                 true,
+	"minItems":             true,
+	"maxItems":             true,
+	"minLength":            true,
+	"maxLength":            true,
+	"minimum":              true,
+	"maximum":              true,
+}
+
+// annotationKeywords carry no validation rules and are accepted as is.
+var annotationKeywords = map[string]bool{
+	"$schema":     true,
+	"$id":         true,
+	"$comment":    true,
+	"title":       true,
+	"description": true,
+	"default":     true,
+	"examples":    true,
+}
+
+func Load(path string) (Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+
+	return Parse(data)
+}
+
+func Parse(data []byte) (Schema, error) {
+	var schema Schema
+
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("decode schema: %w", err)
+	}
+
+	if schema == nil {
+		return nil, fmt.Errorf("schema must be a JSON object")
+	}
+
+	if err := checkKeywords(schema, "$"); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+func checkKeywords(schema Schema, path string) error {
+	keys := make([]string, 0, len(schema))
+	for k := range schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if annotationKeywords[key] {
+			continue
+		}
+
+		if !supportedKeywords[key] {
+			return fmt.Errorf("%s: unsupported schema keyword %q", path, key)
+		}
+
+		switch key {
+		case "properties":
+			properties, ok := schema[key].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s.properties: must be an object", path)
+			}
+
+			names := make([]string, 0, len(properties))
+			for name := range properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				sub, ok := properties[name].(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("%s.properties.%s: must be a schema object", path, name)
+				}
+
+				if err := checkKeywords(sub, path+".properties."+name); err != nil {
+					return err
+				}
+			}
+		case "items":
+			sub, ok := schema[key].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s.items: must be a schema object", path)
+			}
+
+			if err := checkKeywords(sub, path+".items"); err != nil {
+				return err
+			}
+		case "additionalProperties":
+			switch sub := schema[key].(type) {
+			case bool:
+			case map[string]interface{}:
+				if err := checkKeywords(sub, path+".additionalProperties"); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("%s.additionalProperties: must be a boolean or a schema object", path)
+			}
+		}
+	}
+
+	return nil
+}
+
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+func Validate(schema Schema, value interface{}) error {
+	var problems []string
+
+	validate(schema, value, "$", &problems)
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func validate(schema Schema, value interface{}, path string, problems *[]string) {
+	if schema == nil {
+		return
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !matchesAnyType(types, value) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(types, " or "), typeOf(value)))
+
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		*problems = append(*problems, fmt.Sprintf("%s: value is not one of the allowed values", path))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateObject(schema, v, path, problems)
+	case []interface{}:
+		validateArray(schema, v, path, problems)
+	case string:
+		length := len([]rune(v))
+
+		if min, ok := number(schema["minLength"]); ok && float64(length) < min {
+			*problems = append(*problems, fmt.Sprintf("%s: string shorter than %.0f", path, min))
+		}
+
+		if max, ok := number(schema["maxLength"]); ok && float64(length) > max {
+			*problems = append(*problems, fmt.Sprintf("%s: string longer than %.0f", path, max))
+		}
+	case float64:
+		if min, ok := number(schema["minimum"]); ok && v < min {
+			*problems = append(*problems, fmt.Sprintf("%s: %v is less than minimum %v", path, v, min))
+		}
+
+		if max, ok := number(schema["maximum"]); ok && v > max {
+			*problems = append(*problems, fmt.Sprintf("%s: %v is greater than maximum %v", path, v, max))
+		}
+	}
+}
+
+func validateObject(schema Schema, obj map[string]interface{}, path string, problems *[]string) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok {
+				continue
+			}
+
+			if _, exists := obj[name]; !exists {
+				*problems = append(*problems, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propPath := path + "." + key
+
+		if propSchema, ok := properties[key].(map[string]interface{}); ok {
+			validate(propSchema, obj[key], propPath, problems)
+
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*problems = append(*problems, fmt.Sprintf("%s: property is not allowed", propPath))
+			}
+		case map[string]interface{}:
+			validate(additional, obj[key], propPath, problems)
+		}
+	}
+}
+
+func validateArray(schema Schema, arr []interface{}, path string, problems *[]string) {
+	if min, ok := number(schema["minItems"]); ok && float64(len(arr)) < min {
+		*problems = append(*problems, fmt.Sprintf("%s: expected at least %.0f items, got %d", path, min, len(arr)))
+	}
+
+	if max, ok := number(schema["maxItems"]); ok && float64(len(arr)) > max {
+		*problems = append(*problems, fmt.Sprintf("%s: expected at most %.0f items, got %d", path, max, len(arr)))
+	}
+
+	items, ok := schema["items"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for i, item := range arr {
+		validate(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+	}
+}
+
+func schemaTypes(raw interface{}) []string {
+	switch t := raw.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+
+		return types
+	default:
+		return nil
+	}
+}
+
+func matchesAnyType(types []string, value interface{}) bool {
+	actual := typeOf(value)
+
+	for _, t := range types {
+		if t == actual {
+			return true
+		}
+
+		if t == "number" && actual == "integer" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func containsValue(enum []interface{}, value interface{}) bool {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+
+	for _, candidate := range enum {
+		c, err := json.Marshal(candidate)
+		if err == nil && string(c) == string(encoded) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func number(raw interface{}) (float64, bool) {
+	v, ok := raw.(float64)
+
+	return v, ok
+}
@@ -0,0 +1,111 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{name: "supported keywords", schema: `{"type":"object","properties":{"a":{"type":"string","minLength":1}},"required":["a"],"additionalProperties":false}`},
+		{name: "annotations", schema: `{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"t","description":"d","type":"string"}`},
+		{name: "nested items", schema: `{"type":"array","items":{"type":"object","additionalProperties":{"type":"number"}}}`},
+		{name: "not an object", schema: `[]`, wantErr: "decode schema"},
+		{name: "null", schema: `null`, wantErr: "must be a JSON object"},
+		{name: "ref", schema: `{"$ref":"#/defs/a"}`, wantErr: `$: unsupported schema keyword "$ref"`},
+		{name: "oneOf", schema: `{"oneOf":[{"type":"string"}]}`, wantErr: `unsupported schema keyword "oneOf"`},
+		{name: "nested pattern", schema: `{"type":"object","properties":{"a":{"type":"string","pattern":"^x"}}}`, wantErr: `$.properties.a: unsupported schema keyword "pattern"`},
+		{name: "format in items", schema: `{"type":"array","items":{"format":"email"}}`, wantErr: `$.items: unsupported schema keyword "format"`},
+		{name: "const in additionalProperties", schema: `{"additionalProperties":{"const":1}}`, wantErr: `$.additionalProperties: unsupported schema keyword "const"`},
+		{name: "bad properties", schema: `{"properties":[]}`, wantErr: "must be an object"},
+		{name: "bad items", schema: `{"items":[{"type":"string"}]}`, wantErr: "must be a schema object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.schema))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		value    string
+		problems []string
+	}{
+		{name: "string ok", schema: `{"type":"string"}`, value: `"x"`},
+		{name: "type mismatch", schema: `{"type":"string"}`, value: `123`, problems: []string{"$: expected string, got integer"}},
+		{name: "integer is a number", schema: `{"type":"number"}`, value: `3`},
+		{name: "fraction is not an integer", schema: `{"type":"integer"}`, value: `1.5`, problems: []string{"$: expected integer, got number"}},
+		{name: "type list", schema: `{"type":["string","null"]}`, value: `null`},
+		{name: "enum", schema: `{"enum":["a","b"]}`, value: `"c"`, problems: []string{"$: value is not one of the allowed values"}},
+		{name: "string length", schema: `{"type":"string","minLength":2,"maxLength":3}`, value: `"abcd"`, problems: []string{"$: string longer than 3"}},
+		{name: "string length in runes", schema: `{"type":"string","maxLength":3}`, value: `"абв"`},
+		{name: "number range", schema: `{"minimum":1,"maximum":5}`, value: `0`, problems: []string{"$: 0 is less than minimum 1"}},
+		{
+			name:     "object",
+			schema:   `{"type":"object","properties":{"name":{"type":"string"},"age":{"type":"integer"}},"required":["name","email"],"additionalProperties":false}`,
+			value:    `{"name":"x","age":"7","extra":true}`,
+			problems: []string{`$: missing required property "email"`, "$.age: expected integer, got string", "$.extra: property is not allowed"},
+		},
+		{name: "additional properties schema", schema: `{"type":"object","additionalProperties":{"type":"number"}}`, value: `{"a":1,"b":"2"}`, problems: []string{"$.b: expected number, got string"}},
+		{
+			name:     "array",
+			schema:   `{"type":"array","minItems":1,"maxItems":2,"items":{"type":"string"}}`,
+			value:    `["a",1,"c"]`,
+			problems: []string{"$: expected at most 2 items, got 3", "$[1]: expected string, got integer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Parse([]byte(tt.schema))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatalf("decode value: %v", err)
+			}
+
+			err = Validate(schema, value)
+
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			verr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+
+			if strings.Join(verr.Problems, "\n") != strings.Join(tt.problems, "\n") {
+				t.Fatalf("Validate() problems = %q, want %q", verr.Problems, tt.problems)
+			}
+		})
+	}
+}
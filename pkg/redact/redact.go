@@ -0,0 +1,151 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	DetectorEmail    = "email"
+	DetectorPhone    = "phone"
+	DetectorCard     = "card"
+	DetectorURLToken = "url_token"
+	DetectorToken    = "token"
+)
+
+type detector struct {
+	pattern *regexp.Regexp
+	replace func(match []string) string
+}
+
+var detectors = map[string]detector{
+	DetectorEmail: {
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+		replace: func([]string) string { return "[EMAIL]" },
+	},
+	DetectorCard: {
+		pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		replace: func(m []string) string {
+			if !luhnValid(m[0]) {
+				return m[0]
+			}
+
+			return "[CARD]"
+		},
+	},
+	DetectorPhone: {
+		// A number needs a leading + or the usual (555) 123-4567 grouping,
+		// so dates, timestamps and long ids are left alone.
+		pattern: regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?(?:\(\d{1,4}\)[\s.-]?)?\d{1,4}(?:[\s.-]?\d{2,4}){1,4}|\(\d{3}\)\s?\d{3}[\s.-]\d{4}|\b\d{3}[\s.-]\d{3}[\s.-]\d{4})\b`),
+		replace: func(m []string) string {
+			digits := countDigits(m[0])
+			if digits < 10 || digits > 15 {
+				return m[0]
+			}
+
+			return "[PHONE]"
+		},
+	},
+	DetectorURLToken: {
+		pattern: regexp.MustCompile(`(?i)([?&#;](?:access_token|id_token|refresh_token|token|api_key|apikey|key|secret|password|passwd|pwd|session|sessionid|sid|auth|code|signature|sig)=)[^&#\s"']+`),
+		replace: func(m []string) string { return m[1] + "[REDACTED]" },
+	},
+	DetectorToken: {
+		pattern: regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/-]+=*|eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]+`),
+		replace: func([]string) string { return "[TOKEN]" },
+	},
+}
+
+// Cards are checked before phones so that a card number is not reported as
+// a phone number, tokens before emails so JWTs in URLs are caught whole.
+var detectorOrder = []string{DetectorURLToken, DetectorToken, DetectorEmail, DetectorCard, DetectorPhone}
+
+type Redactor struct {
+	active []detector
+	extra  []func(string) string
+}
+
+func New(names []string, extra ...func(string) string) (*Redactor, error) {
+	enabled := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if _, ok := detectors[name]; !ok {
+			return nil, fmt.Errorf("unknown redaction detector %q", name)
+		}
+
+		enabled[name] = true
+	}
+
+	r := &Redactor{extra: extra}
+
+	for _, name := range detectorOrder {
+		if enabled[name] {
+			r.active = append(r.active, detectors[name])
+		}
+	}
+
+	return r, nil
+}
+
+func (r *Redactor) String(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+
+	for _, mask := range r.extra {
+		text = mask(text)
+	}
+
+	for _, d := range r.active {
+		text = d.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			return d.replace(d.pattern.FindStringSubmatch(match))
+		})
+	}
+
+	return text
+}
+
+func luhnValid(number string) bool {
+	sum := 0
+	double := false
+	digits := 0
+
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		double = !double
+		digits++
+	}
+
+	return digits >= 13 && sum%10 == 0
+}
+
+func countDigits(s string) int {
+	n := 0
+
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,49 @@
+package redact
+
+import (
+	"testing"
+)
+
+func TestRedactorString(t *testing.T) {
+	r, err := New([]string{DetectorEmail, DetectorPhone, DetectorCard, DetectorURLToken, DetectorToken})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "international phone", text: "call +1 555 123 4567 now", want: "call [PHONE] now"},
+		{name: "international with area code", text: "tel: +44 (20) 7946-0958", want: "tel: [PHONE]"},
+		{name: "compact international", text: "+79161234567", want: "[PHONE]"},
+		{name: "grouped phone", text: "phone 555-123-4567.", want: "phone [PHONE]."},
+		{name: "area code in parens", text: "(555) 123-4567", want: "[PHONE]"},
+		{name: "iso date", text: "due 2024-01-15", want: "due 2024-01-15"},
+		{name: "timestamp", text: "at 2024-01-15 12:30:45.123", want: "at 2024-01-15 12:30:45.123"},
+		{name: "unix millis", text: "ts=1700000000123", want: "ts=1700000000123"},
+		{name: "order id", text: "order #12345678901", want: "order #12345678901"},
+		{name: "dotted date", text: "15.01.2024 - 20.01.2024", want: "15.01.2024 - 20.01.2024"},
+		{name: "version", text: "v1.2.3", want: "v1.2.3"},
+		{name: "card", text: "card 4111 1111 1111 1111", want: "card [CARD]"},
+		{name: "card failing luhn", text: "id 4111 1111 1111 1112", want: "id 4111 1111 1111 1112"},
+		{name: "email", text: "mail john.doe@example.com", want: "mail [EMAIL]"},
+		{name: "url token", text: "https://x.io/cb?code=abc123&state=1", want: "https://x.io/cb?code=[REDACTED]&state=1"},
+		{name: "bearer", text: "Authorization: Bearer abc.def", want: "Authorization: [TOKEN]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.String(tt.text); got != tt.want {
+				t.Fatalf("String(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUnknownDetector(t *testing.T) {
+	if _, err := New([]string{"ssn"}); err == nil {
+		t.Fatal("New() error = nil, want unknown detector error")
+	}
+}
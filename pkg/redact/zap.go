@@ -0,0 +1,113 @@
+package redact
+
+import (
+	"encoding/json"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type core struct {
+	zapcore.Core
+	redactor *Redactor
+}
+
+func WrapCore(c zapcore.Core, r *Redactor) zapcore.Core {
+	return &core{Core: c, redactor: r}
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{
+		Core:     c.Core.With(c.redactFields(fields)),
+		redactor: c.redactor,
+	}
+}
+
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.redactor.String(entry.Message)
+
+	return c.Core.Write(entry, c.redactFields(fields))
+}
+
+func (c *core) redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+
+	for i, f := range fields {
+		switch f.Type {
+		case zapcore.StringType:
+			f.String = c.redactor.String(f.String)
+		case zapcore.ErrorType:
+			if err, ok := f.Interface.(error); ok && err != nil {
+				f = zapcore.Field{Key: f.Key, Type: zapcore.StringType, String: c.redactor.String(err.Error())}
+			}
+		case zapcore.StringerType:
+			if s, ok := f.Interface.(interface{ String() string }); ok {
+				f = zapcore.Field{Key: f.Key, Type: zapcore.StringType, String: c.redactor.String(s.String())}
+			}
+		case zapcore.ByteStringType:
+			if b, ok := f.Interface.([]byte); ok {
+				f = zapcore.Field{Key: f.Key, Type: zapcore.StringType, String: c.redactor.String(string(b))}
+			}
+		case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType, zapcore.ReflectType:
+			if value, ok := c.redactValue(f); ok {
+				f = zapcore.Field{Key: f.Key, Type: zapcore.ReflectType, Interface: value}
+			}
+		}
+
+		redacted[i] = f
+	}
+
+	return redacted
+}
+
+// redactValue encodes an object, array or reflected field into plain maps
+// and slices and redacts every string inside it.
+func (c *core) redactValue(f zapcore.Field) (interface{}, bool) {
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+
+	value, ok := enc.Fields[f.Key]
+	if !ok {
+		return nil, false
+	}
+
+	if f.Type == zapcore.ReflectType {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, false
+		}
+
+		value = nil
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, false
+		}
+	}
+
+	return c.redactTree(value), true
+}
+
+func (c *core) redactTree(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return c.redactor.String(v)
+	case []byte:
+		return c.redactor.String(string(v))
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = c.redactTree(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = c.redactTree(item)
+		}
+	}
+
+	return value
+}
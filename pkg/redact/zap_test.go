@@ -0,0 +1,69 @@
+package redact
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type account struct {
+	Email string
+	Tags  []string
+}
+
+func (a account) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("email", a.Email)
+
+	return enc.AddArray("tags", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		for _, tag := range a.Tags {
+			arr.AppendString(tag)
+		}
+
+		return nil
+	}))
+}
+
+func TestCoreRedactsFields(t *testing.T) {
+	r, err := New([]string{DetectorEmail})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	obs, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(WrapCore(obs, r)).With(zap.String("user", "a@example.com"))
+
+	logger.Info("sent to b@example.com",
+		zap.ByteString("body", []byte("to c@example.com")),
+		zap.Object("account", account{Email: "d@example.com", Tags: []string{"e@example.com"}}),
+		zap.Strings("cc", []string{"f@example.com"}),
+		zap.Any("meta", map[string]string{"owner": "g@example.com"}),
+	)
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+
+	if got := entries[0].Message; got != "sent to [EMAIL]" {
+		t.Fatalf("message = %q", got)
+	}
+
+	fields := entries[0].ContextMap()
+
+	want := map[string]interface{}{
+		"user":    "[EMAIL]",
+		"body":    "to [EMAIL]",
+		"account": map[string]interface{}{"email": "[EMAIL]", "tags": []interface{}{"[EMAIL]"}},
+		"cc":      []interface{}{"[EMAIL]"},
+		"meta":    map[string]interface{}{"owner": "[EMAIL]"},
+	}
+
+	for key, value := range want {
+		if got := fields[key]; !reflect.DeepEqual(got, value) {
+			t.Errorf("field %s = %#v, want %#v", key, got, value)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package tracing
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -9,6 +10,16 @@ import (
 	"go.uber.org/zap"
 )
 
+var redactString = func(s string) string { return s }
+
+// SetRedactor installs a filter applied to every string attribute, event
+// attribute and error message before it is recorded on a span.
+func SetRedactor(fn func(string) string) {
+	if fn != nil {
+		redactString = fn
+	}
+}
+
 type Span struct {
 	span   trace.Span
 	logger *zap.Logger
@@ -16,7 +27,7 @@ type Span struct {
 }
 
 func StartSpan(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, name string, attrs ...attribute.KeyValue) (context.Context, *Span) {
-	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(redactAttributes(attrs)...))
 
 	return ctx, &Span{
 		span:   span,
@@ -27,8 +38,9 @@ func StartSpan(ctx context.Context, tracer trace.Tracer, logger *zap.Logger, nam
 
 func (s *Span) End(err error) {
 	if err != nil {
-		s.span.SetStatus(codes.Error, err.Error())
-		s.span.RecordError(err)
+		msg := redactString(err.Error())
+		s.span.SetStatus(codes.Error, msg)
+		s.span.RecordError(errors.New(msg))
 	} else {
 		s.span.SetStatus(codes.Ok, "")
 	}
@@ -37,9 +49,19 @@ func (s *Span) End(err error) {
 }
 
 func (s *Span) AddEvent(name string, attrs ...attribute.KeyValue) {
-	s.span.AddEvent(name, trace.WithAttributes(attrs...))
+	s.span.AddEvent(name, trace.WithAttributes(redactAttributes(attrs)...))
 }
 
 func (s *Span) SetAttributes(attrs ...attribute.KeyValue) {
-	s.span.SetAttributes(attrs...)
+	s.span.SetAttributes(redactAttributes(attrs)...)
+}
+
+func redactAttributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	for i, attr := range attrs {
+		if attr.Value.Type() == attribute.STRING {
+			attrs[i] = attribute.String(string(attr.Key), redactString(attr.Value.AsString()))
+		}
+	}
+
+	return attrs
 }